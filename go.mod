@@ -1,8 +1,20 @@
 module github.com/stahlstift/go-metacritic
 
-go 1.12
+go 1.21
 
 require (
 	github.com/hbakhtiyor/strsim v0.0.0-20190107154042-4d2bbb273edf
+	go.opentelemetry.io/otel v1.29.0
+	go.opentelemetry.io/otel/sdk v1.29.0
+	go.opentelemetry.io/otel/trace v1.29.0
 	golang.org/x/net v0.0.0-20190522155817-f3200d17e092
+	golang.org/x/time v0.3.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	go.opentelemetry.io/otel/metric v1.29.0 // indirect
+	golang.org/x/sys v0.24.0 // indirect
 )
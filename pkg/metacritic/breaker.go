@@ -0,0 +1,291 @@
+package metacritic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState int
+
+// All states of a CircuitBreaker.
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+// CircuitBreaker is a Crawler failing fast with ErrCircuitOpen while metacritic is unavailable.
+//
+// The urls the circuit admits are passed to the wrapped Crawler as one batch, so
+// its concurrency limit still applies, and every result is counted on its own.
+// The circuit opens after FailureThreshold failed urls within FailureWindow.
+// While open, no request is sent. After CoolDown a single url is crawled as
+// probe while the others wait: on success the circuit closes, otherwise it
+// opens again.
+//
+// At most FailureThreshold urls are crawled at once, less the failures already
+// counted, so an outage costs no more than FailureThreshold requests.
+//
+// Failures are transport errors, 403, 429 and 5xx responses. Results of a
+// cancelled context and other status codes like 404 are not counted.
+//
+// A CircuitBreaker is safe for concurrent use.
+type CircuitBreaker struct {
+	Crawler          Crawler
+	FailureThreshold int
+
+	// FailureWindow is how long a failure is counted, zero counts all failures
+	// since the circuit closed.
+	FailureWindow time.Duration
+	CoolDown      time.Duration
+
+	mu       sync.Mutex
+	changed  chan struct{} // closed and replaced when a request is released
+	state    CircuitState
+	failures []time.Time
+	inFlight int
+	openedAt time.Time
+}
+
+// NewCircuitBreaker returns a CircuitBreaker wrapping crawler, which opens
+// after 5 failures within a minute and probes again after 30 seconds.
+func NewCircuitBreaker(crawler Crawler) *CircuitBreaker {
+	return &CircuitBreaker{
+		Crawler:          crawler,
+		FailureThreshold: 5,
+		FailureWindow:    time.Minute,
+		CoolDown:         time.Second * 30,
+	}
+}
+
+// State returns the current state of the circuit.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// Crawl crawls urls with the wrapped Crawler unless the circuit is open.
+func (b *CircuitBreaker) Crawl(urls []string) []*Result {
+	return b.CrawlWithContext(context.Background(), urls)
+}
+
+// CrawlWithContext is like Crawl but passes ctx to a wrapped ContextCrawler.
+func (b *CircuitBreaker) CrawlWithContext(ctx context.Context, urls []string) []*Result {
+	results := make([]*Result, len(urls))
+
+	for start := 0; start < len(urls); {
+		n, err := b.acquire(ctx, len(urls)-start)
+		if err != nil {
+			for i := start; i < len(urls); i++ {
+				results[i] = rejected(urls[i], err)
+			}
+			break
+		}
+
+		for i, result := range crawl(ctx, b.Crawler, urls[start:start+n]) {
+			b.release(ctx, result)
+			results[start+i] = result
+		}
+		start += n
+	}
+
+	return results
+}
+
+// CrawlOne calls Crawl returning the first element.
+func (b *CircuitBreaker) CrawlOne(url string) *Result {
+	return b.CrawlOneWithContext(context.Background(), url)
+}
+
+// CrawlOneWithContext calls CrawlWithContext returning the first element.
+func (b *CircuitBreaker) CrawlOneWithContext(ctx context.Context, url string) *Result {
+	return b.CrawlWithContext(ctx, []string{url})[0]
+}
+
+// rejected returns the Result of url not crawled because of err.
+func rejected(url string, err error) *Result {
+	if err == ErrCircuitOpen {
+		err = fmt.Errorf("%w: %s", ErrCircuitOpen, url)
+	}
+
+	return &Result{Error: err, URL: url, FinalURL: url}
+}
+
+// threshold returns FailureThreshold but at least 1.
+func (b *CircuitBreaker) threshold() int {
+	if b.FailureThreshold < 1 {
+		return 1
+	}
+
+	return b.FailureThreshold
+}
+
+// wait blocks until the state changes, a request is released or ctx is done.
+// b.mu must be held, it is unlocked while waiting.
+func (b *CircuitBreaker) wait(ctx context.Context) {
+	if b.changed == nil {
+		b.changed = make(chan struct{})
+	}
+	changed := b.changed
+
+	b.mu.Unlock()
+	defer b.mu.Lock()
+
+	select {
+	case <-changed:
+	case <-ctx.Done():
+	}
+}
+
+// broadcast wakes all waiting calls. b.mu must be held.
+func (b *CircuitBreaker) broadcast() {
+	if b.changed != nil {
+		close(b.changed)
+		b.changed = nil
+	}
+}
+
+// prune forgets the failures older than FailureWindow. b.mu must be held.
+func (b *CircuitBreaker) prune(now time.Time) {
+	if b.FailureWindow <= 0 {
+		return
+	}
+
+	i := 0
+	for i < len(b.failures) && now.Sub(b.failures[i]) >= b.FailureWindow {
+		i++
+	}
+	b.failures = b.failures[i:]
+}
+
+// admit reserves a request slot if the circuit allows one. It returns false if
+// the caller has to wait and ErrCircuitOpen if the circuit is open. b.mu must be held.
+func (b *CircuitBreaker) admit() (bool, error) {
+	switch b.state {
+	case CircuitOpen:
+		if time.Since(b.openedAt) < b.CoolDown {
+			return false, ErrCircuitOpen
+		}
+		// this request is the probe
+		b.state = CircuitHalfOpen
+		b.inFlight++
+		return true, nil
+	case CircuitClosed:
+		b.prune(time.Now())
+		if b.inFlight < b.threshold()-len(b.failures) {
+			b.inFlight++
+			return true, nil
+		}
+	}
+
+	// a probe is in flight or the closed circuit is at capacity
+	return false, nil
+}
+
+// acquire blocks until a request may be sent and then reserves up to n request
+// slots. It returns the number of slots, ErrCircuitOpen if the circuit is open
+// or ctx.Err() if ctx is done.
+func (b *CircuitBreaker) acquire(ctx context.Context, n int) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		ok, err := b.admit()
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			break
+		}
+
+		b.wait(ctx)
+	}
+
+	acquired := 1
+	for acquired < n {
+		if ok, _ := b.admit(); !ok {
+			break
+		}
+		acquired++
+	}
+
+	return acquired, nil
+}
+
+// release updates the state with the result of an acquired request.
+func (b *CircuitBreaker) release(ctx context.Context, result *Result) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	defer b.broadcast()
+
+	b.inFlight--
+
+	if ctx.Err() != nil || result == nil {
+		if b.state == CircuitHalfOpen {
+			// the probe was cancelled, the next call probes again
+			b.state = CircuitOpen
+		}
+		return
+	}
+
+	if !isOutage(result.Error) {
+		if b.state == CircuitHalfOpen {
+			b.state = CircuitClosed
+			b.failures = nil
+		}
+		return
+	}
+
+	if b.state == CircuitOpen {
+		return
+	}
+
+	now := time.Now()
+	b.failures = append(b.failures, now)
+	b.prune(now)
+	if b.state == CircuitHalfOpen || len(b.failures) >= b.threshold() {
+		b.state = CircuitOpen
+		b.openedAt = now
+		b.failures = nil
+	}
+}
+
+// isOutage reports whether err indicates metacritic is down or blocking requests.
+func isOutage(err error) bool {
+	if err == nil || errors.Is(err, ErrDisallowedByRobots) || errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500 ||
+			statusErr.StatusCode == http.StatusTooManyRequests ||
+			statusErr.StatusCode == http.StatusForbidden
+	}
+
+	return true
+}
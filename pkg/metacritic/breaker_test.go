@@ -0,0 +1,249 @@
+package metacritic_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stahlstift/go-metacritic/pkg/metacritic"
+)
+
+// outageCrawler returns a crawler answering with status and counting the requests.
+func outageCrawler(status *int32, requests *int32) *metacritic.DefaultCrawler {
+	mock := &MockClient{}
+	mock.DoFn = func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(requests, 1)
+		rec := httptest.NewRecorder()
+		rec.WriteHeader(int(atomic.LoadInt32(status)))
+		return rec.Result(), nil
+	}
+
+	return &metacritic.DefaultCrawler{
+		Client:     mock,
+		Concurrent: 2,
+		UserAgent:  userAgent,
+	}
+}
+
+func TestCircuitBreakerOpens(t *testing.T) {
+	t.Parallel()
+
+	status, requests := int32(http.StatusServiceUnavailable), int32(0)
+	b := metacritic.NewCircuitBreaker(outageCrawler(&status, &requests))
+	b.FailureThreshold = 3
+	b.CoolDown = time.Hour
+
+	_ = b.CrawlWithContext(context.Background(), []string{"http://a/1", "http://a/2", "http://a/3"})
+	if b.State() != metacritic.CircuitOpen {
+		t.Fatalf("State() returned '%s' instead of open", b.State())
+	}
+
+	results := b.CrawlWithContext(context.Background(), []string{"http://a/4", "http://a/5"})
+	for _, result := range results {
+		if !errors.Is(result.Error, metacritic.ErrCircuitOpen) {
+			t.Fatalf("CrawlWithContext() returned '%v' instead of ErrCircuitOpen", result.Error)
+		}
+	}
+	if requests != 3 {
+		t.Fatalf("CrawlWithContext() sent %d requests while open", requests-3)
+	}
+}
+
+func TestCircuitBreakerIgnoresNotFound(t *testing.T) {
+	t.Parallel()
+
+	status, requests := int32(http.StatusNotFound), int32(0)
+	b := metacritic.NewCircuitBreaker(outageCrawler(&status, &requests))
+	b.FailureThreshold = 1
+
+	_ = b.CrawlWithContext(context.Background(), []string{"http://a/1", "http://a/2"})
+	if b.State() != metacritic.CircuitClosed {
+		t.Fatalf("State() returned '%s' instead of closed", b.State())
+	}
+}
+
+func TestCircuitBreakerProbe(t *testing.T) {
+	t.Parallel()
+
+	status, requests := int32(http.StatusServiceUnavailable), int32(0)
+	b := metacritic.NewCircuitBreaker(outageCrawler(&status, &requests))
+	b.FailureThreshold = 1
+	b.CoolDown = 20 * time.Millisecond
+
+	_ = b.CrawlOneWithContext(context.Background(), "http://a/1")
+
+	// failing probe opens again and rejects the rest
+	time.Sleep(b.CoolDown)
+	results := b.CrawlWithContext(context.Background(), []string{"http://a/2", "http://a/3"})
+	rejected := 0
+	for _, result := range results {
+		if errors.Is(result.Error, metacritic.ErrCircuitOpen) {
+			rejected++
+		}
+	}
+	if rejected != 1 || atomic.LoadInt32(&requests) != 2 {
+		t.Fatalf("CrawlWithContext() sent %d requests and rejected %d urls instead of a single probe", requests, rejected)
+	}
+	if b.State() != metacritic.CircuitOpen {
+		t.Fatalf("State() returned '%s' instead of open", b.State())
+	}
+
+	// successful probe closes and crawls the rest
+	atomic.StoreInt32(&status, http.StatusOK)
+	time.Sleep(b.CoolDown)
+	results = b.CrawlWithContext(context.Background(), []string{"http://a/4", "http://a/5"})
+	for _, result := range results {
+		if result.Error != nil {
+			t.Fatalf("CrawlWithContext() returned an error '%s'", result.Error)
+		}
+		result.Response.Body.Close()
+	}
+	if b.State() != metacritic.CircuitClosed {
+		t.Fatalf("State() returned '%s' instead of closed", b.State())
+	}
+	if requests != 4 {
+		t.Fatalf("CrawlWithContext() sent %d requests instead of 4", requests)
+	}
+}
+
+func TestCircuitBreakerStopsBatch(t *testing.T) {
+	t.Parallel()
+
+	status, requests := int32(http.StatusServiceUnavailable), int32(0)
+	b := metacritic.NewCircuitBreaker(outageCrawler(&status, &requests))
+	b.FailureThreshold = 3
+	b.CoolDown = time.Hour
+
+	urls := make([]string, 10)
+	for i := range urls {
+		urls[i] = "http://a/" + strconv.Itoa(i)
+	}
+
+	results := b.CrawlWithContext(context.Background(), urls)
+	if requests != 3 {
+		t.Fatalf("CrawlWithContext() sent %d requests instead of 3", requests)
+	}
+
+	rejected := 0
+	for _, result := range results {
+		if errors.Is(result.Error, metacritic.ErrCircuitOpen) {
+			rejected++
+		}
+	}
+	if rejected != 7 {
+		t.Fatalf("CrawlWithContext() rejected %d urls instead of 7", rejected)
+	}
+}
+
+func TestCircuitBreakerInterleavedSuccesses(t *testing.T) {
+	t.Parallel()
+
+	var requests int32
+	mock := &MockClient{}
+	mock.DoFn = func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&requests, 1)
+		rec := httptest.NewRecorder()
+		if strings.HasPrefix(req.URL.Path, "/fail") {
+			rec.WriteHeader(http.StatusServiceUnavailable)
+		}
+		return rec.Result(), nil
+	}
+	b := metacritic.NewCircuitBreaker(&metacritic.DefaultCrawler{Client: mock, Concurrent: 2, UserAgent: userAgent})
+	b.FailureThreshold = 3
+	b.CoolDown = time.Hour
+
+	var urls []string
+	for i := 0; i < 5; i++ {
+		urls = append(urls, "http://a/fail/"+strconv.Itoa(i), "http://a/ok/"+strconv.Itoa(i))
+	}
+	for _, result := range b.CrawlWithContext(context.Background(), urls) {
+		if result.Error == nil {
+			result.Response.Body.Close()
+		}
+	}
+
+	if b.State() != metacritic.CircuitOpen {
+		t.Fatalf("State() returned '%s' instead of open", b.State())
+	}
+}
+
+func TestCircuitBreakerWaitCancelled(t *testing.T) {
+	t.Parallel()
+
+	block := make(chan struct{})
+	mock := &MockClient{}
+	mock.DoFn = func(req *http.Request) (*http.Response, error) {
+		select {
+		case <-block:
+		case <-time.After(2 * time.Second):
+		}
+		return httptest.NewRecorder().Result(), nil
+	}
+	b := metacritic.NewCircuitBreaker(&metacritic.DefaultCrawler{Client: mock, Concurrent: 1, UserAgent: userAgent})
+	b.FailureThreshold = 1
+
+	// the only request slot is taken until block is closed
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = b.CrawlOne("http://a/1")
+	}()
+	defer func() {
+		close(block)
+		<-done
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	result := b.CrawlOneWithContext(ctx, "http://a/2")
+	if !errors.Is(result.Error, context.DeadlineExceeded) {
+		t.Fatalf("CrawlOneWithContext() returned '%v' instead of '%s'", result.Error, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("CrawlOneWithContext() returned after %s", elapsed)
+	}
+}
+
+func TestCircuitBreakerKeepsConcurrency(t *testing.T) {
+	t.Parallel()
+
+	var inFlight, maxInFlight int32
+	mock := &MockClient{}
+	mock.DoFn = func(req *http.Request) (*http.Response, error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			top := atomic.LoadInt32(&maxInFlight)
+			if n <= top || atomic.CompareAndSwapInt32(&maxInFlight, top, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		return httptest.NewRecorder().Result(), nil
+	}
+	b := metacritic.NewCircuitBreaker(&metacritic.DefaultCrawler{Client: mock, Concurrent: 1, UserAgent: userAgent})
+
+	urls := make([]string, 10)
+	for i := range urls {
+		urls[i] = "http://a/" + strconv.Itoa(i)
+	}
+	for _, result := range b.Crawl(urls) {
+		if result.Error != nil {
+			t.Fatalf("Crawl() returned an error '%s'", result.Error)
+		}
+		result.Response.Body.Close()
+	}
+
+	if maxInFlight != 1 {
+		t.Fatalf("Crawl() sent %d requests at once instead of 1", maxInFlight)
+	}
+}
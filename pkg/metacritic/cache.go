@@ -0,0 +1,190 @@
+package metacritic
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PageType is the kind of metacritic page an url points to.
+type PageType string
+
+const (
+	SearchPage PageType = "search" // search result page
+	DetailPage PageType = "detail" // game detail page
+	ReviewPage PageType = "review" // critic or user reviews of a game
+	OtherPage  PageType = "other"
+)
+
+// PageTypeOf returns the PageType of the metacritic url rawurl.
+func PageTypeOf(rawurl string) PageType {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return OtherPage
+	}
+
+	switch {
+	case strings.HasPrefix(u.Path, "/search/"):
+		return SearchPage
+	case strings.HasPrefix(u.Path, "/game/") &&
+		(strings.HasSuffix(u.Path, "/critic-reviews") || strings.HasSuffix(u.Path, "/user-reviews")):
+		return ReviewPage
+	case strings.HasPrefix(u.Path, "/game/"):
+		return DetailPage
+	}
+
+	return OtherPage
+}
+
+// CacheEntry is a response stored in a Cache.
+type CacheEntry struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	Stored     time.Time
+}
+
+// response returns a new *http.Response for the entry.
+func (e *CacheEntry) response() *http.Response {
+	return &http.Response{
+		Status:        strconv.Itoa(e.StatusCode) + " " + http.StatusText(e.StatusCode),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+	}
+}
+
+// Cache is the storage of the CachingCrawler.
+//
+// Implementations must be safe for concurrent use. Entries are returned
+// regardless of their age - the CachingCrawler decides if they are fresh.
+type Cache interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, entry *CacheEntry)
+	Delete(key string)
+}
+
+// DefaultCacheTTL are the TTLs used by NewCachingCrawler.
+var DefaultCacheTTL = map[PageType]time.Duration{
+	SearchPage: 15 * time.Minute,
+	DetailPage: 6 * time.Hour,
+	ReviewPage: 6 * time.Hour,
+}
+
+// CachingCrawler is a Crawler serving responses from a Cache and crawling
+// only the missing or expired urls with the wrapped Crawler.
+type CachingCrawler struct {
+	Crawler Crawler
+	Cache   Cache
+
+	// TTL is how long the responses of a PageType are served from the cache.
+	// Page types without a TTL are not cached.
+	TTL map[PageType]time.Duration
+
+	// Observer is notified about every response served from the cache.
+	Observer Observer
+}
+
+// NewCachingCrawler returns a CachingCrawler wrapping crawler using DefaultCacheTTL.
+func NewCachingCrawler(crawler Crawler, cache Cache) *CachingCrawler {
+	ttl := make(map[PageType]time.Duration, len(DefaultCacheTTL))
+	for pageType, d := range DefaultCacheTTL {
+		ttl[pageType] = d
+	}
+
+	return &CachingCrawler{
+		Crawler: crawler,
+		Cache:   cache,
+		TTL:     ttl,
+	}
+}
+
+// CrawlWithContext is like Crawl but passes ctx to a wrapped ContextCrawler.
+func (c *CachingCrawler) CrawlWithContext(ctx context.Context, urls []string) []*Result {
+	results := make([]*Result, len(urls))
+
+	var missing []string
+	var missingIndex []int
+
+	now := time.Now()
+	for i, u := range urls {
+		ttl := c.TTL[PageTypeOf(u)]
+		if entry, ok := c.Cache.Get(u); ok && ttl > 0 && now.Sub(entry.Stored) < ttl {
+			results[i] = &Result{
+				Response: entry.response(),
+				URL:      u,
+				FinalURL: u,
+				Cached:   true,
+			}
+			observerOrNop(c.Observer).CacheHit(PageTypeOf(u))
+			continue
+		}
+
+		missing = append(missing, u)
+		missingIndex = append(missingIndex, i)
+	}
+
+	if len(missing) == 0 {
+		return results
+	}
+
+	for j, result := range crawl(ctx, c.Crawler, missing) {
+		u := missing[j]
+		if result != nil && result.Error == nil && c.TTL[PageTypeOf(u)] > 0 {
+			result = c.store(u, result)
+		}
+		results[missingIndex[j]] = result
+	}
+
+	return results
+}
+
+// Crawl returns the cached responses for urls and crawls the others.
+func (c *CachingCrawler) Crawl(urls []string) []*Result {
+	return c.CrawlWithContext(context.Background(), urls)
+}
+
+// CrawlOne calls Crawl returning the first element.
+func (c *CachingCrawler) CrawlOne(url string) *Result {
+	return c.CrawlOneWithContext(context.Background(), url)
+}
+
+// CrawlOneWithContext calls CrawlWithContext returning the first element.
+func (c *CachingCrawler) CrawlOneWithContext(ctx context.Context, url string) *Result {
+	return c.CrawlWithContext(ctx, []string{url})[0]
+}
+
+// store reads the body of result into the cache and replaces it with an in memory copy.
+func (c *CachingCrawler) store(url string, result *Result) *Result {
+	body, err := io.ReadAll(result.Response.Body)
+	result.Response.Body.Close()
+	if err != nil {
+		return &Result{
+			Error:    err,
+			URL:      result.URL,
+			FinalURL: result.FinalURL,
+			Latency:  result.Latency,
+			Attempts: result.Attempts,
+		}
+	}
+
+	c.Cache.Set(url, &CacheEntry{
+		StatusCode: result.Response.StatusCode,
+		Header:     result.Response.Header.Clone(),
+		Body:       body,
+		Stored:     time.Now(),
+	})
+
+	result.Response.Body = io.NopCloser(bytes.NewReader(body))
+
+	return result
+}
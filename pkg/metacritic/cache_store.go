@@ -0,0 +1,163 @@
+package metacritic
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// MemoryCache is an in memory Cache evicting the least recently used entries.
+type MemoryCache struct {
+	MaxEntries int // 0 means no limit
+
+	mu      sync.Mutex
+	ll      *list.List
+	entries map[string]*list.Element
+}
+
+type memoryCacheItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// NewMemoryCache returns a MemoryCache holding up to maxEntries entries.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		MaxEntries: maxEntries,
+	}
+}
+
+func (c *MemoryCache) init() {
+	if c.entries == nil {
+		c.ll = list.New()
+		c.entries = make(map[string]*list.Element)
+	}
+}
+
+// Get returns the entry of key and marks it as recently used.
+func (c *MemoryCache) Get(key string) (*CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.init()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	c.ll.MoveToFront(e)
+
+	return e.Value.(*memoryCacheItem).entry, true
+}
+
+// Set stores entry under key, evicting the least recently used entry if the cache is full.
+func (c *MemoryCache) Set(key string, entry *CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.init()
+	if e, ok := c.entries[key]; ok {
+		c.ll.MoveToFront(e)
+		e.Value.(*memoryCacheItem).entry = entry
+		return
+	}
+
+	c.entries[key] = c.ll.PushFront(&memoryCacheItem{key: key, entry: entry})
+
+	if c.MaxEntries > 0 && c.ll.Len() > c.MaxEntries {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryCacheItem).key)
+	}
+}
+
+// Delete removes the entry of key.
+func (c *MemoryCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.init()
+	if e, ok := c.entries[key]; ok {
+		c.ll.Remove(e)
+		delete(c.entries, key)
+	}
+}
+
+// Len returns the number of entries in the cache.
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.init()
+	return c.ll.Len()
+}
+
+// FileCache is a Cache storing every entry as a file in Dir.
+//
+// Errors reading or writing the files are treated as cache misses.
+type FileCache struct {
+	Dir string
+}
+
+// NewFileCache returns a FileCache creating dir if it does not exist.
+func NewFileCache(dir string) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	return &FileCache{Dir: dir}, nil
+}
+
+func (c *FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:]))
+}
+
+// Get reads the entry of key from its file.
+func (c *FileCache) Get(key string) (*CacheEntry, bool) {
+	file, err := os.Open(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	defer file.Close()
+
+	var entry CacheEntry
+	if err := gob.NewDecoder(file).Decode(&entry); err != nil {
+		return nil, false
+	}
+
+	return &entry, true
+}
+
+// Set writes entry to the file of key.
+//
+// The entry is written to a temporary file first, so concurrent readers never see partial entries.
+func (c *FileCache) Set(key string, entry *CacheEntry) {
+	tmp, err := os.CreateTemp(c.Dir, ".tmp-")
+	if err != nil {
+		return
+	}
+
+	err = gob.NewEncoder(tmp).Encode(entry)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// Delete removes the file of key.
+func (c *FileCache) Delete(key string) {
+	os.Remove(c.path(key))
+}
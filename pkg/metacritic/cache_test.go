@@ -0,0 +1,237 @@
+package metacritic_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stahlstift/go-metacritic/pkg/metacritic"
+)
+
+// countingClient answers every request with body and counts the requests.
+func countingClient(body string) (*MockClient, *int32) {
+	var requests int32
+
+	mock := &MockClient{}
+	mock.DoFn = func(req *http.Request) (response *http.Response, err error) {
+		atomic.AddInt32(&requests, 1)
+
+		res := httptest.NewRecorder().Result()
+		res.Body = io.NopCloser(strings.NewReader(body))
+		return res, nil
+	}
+
+	return mock, &requests
+}
+
+func readBody(t *testing.T, res *metacritic.Result) string {
+	if res.Error != nil {
+		t.Fatalf("crawl returned an error '%s'", res.Error)
+	}
+	defer res.Response.Body.Close()
+
+	body, err := io.ReadAll(res.Response.Body)
+	if err != nil {
+		t.Fatalf("error reading body ('%s')", err)
+	}
+
+	return string(body)
+}
+
+func TestPageTypeOf(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]metacritic.PageType{
+		"https://www.metacritic.com/search/game/Mario/results?plats[268409]=1&search_type=advanced": metacritic.SearchPage,
+		"https://www.metacritic.com/game/switch/super-mario-party":                                  metacritic.DetailPage,
+		"https://www.metacritic.com/game/switch/super-mario-party/critic-reviews?page=1":            metacritic.ReviewPage,
+		"https://www.metacritic.com/game/switch/super-mario-party/user-reviews":                     metacritic.ReviewPage,
+		"https://www.metacritic.com/robots.txt":                                                     metacritic.OtherPage,
+	}
+
+	for u, pageType := range tests {
+		if p := metacritic.PageTypeOf(u); p != pageType {
+			t.Errorf("PageTypeOf('%s') returned '%s' instead of '%s'", u, p, pageType)
+		}
+	}
+}
+
+func TestCachingCrawler(t *testing.T) {
+	t.Parallel()
+
+	mock, requests := countingClient("detail page")
+	c := metacritic.NewCachingCrawler(&metacritic.DefaultCrawler{
+		Client:     mock,
+		Concurrent: 1,
+		UserAgent:  userAgent,
+	}, metacritic.NewMemoryCache(10))
+
+	u := "https://www.metacritic.com/game/switch/super-mario-party"
+	if body := readBody(t, c.CrawlOneWithContext(context.Background(), u)); body != "detail page" {
+		t.Fatalf("CrawlOneWithContext() returned the body '%s'", body)
+	}
+
+	res := c.CrawlOneWithContext(context.Background(), u)
+	if !res.Cached {
+		t.Fatal("CrawlOneWithContext() did not serve the response from the cache")
+	}
+
+	if body := readBody(t, res); body != "detail page" {
+		t.Fatalf("CrawlOneWithContext() returned the cached body '%s'", body)
+	}
+
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Fatalf("%d requests were sent instead of 1", n)
+	}
+}
+
+func TestCachingCrawlerTTL(t *testing.T) {
+	t.Parallel()
+
+	mock, requests := countingClient("page")
+	c := metacritic.NewCachingCrawler(&metacritic.DefaultCrawler{
+		Client:     mock,
+		Concurrent: 2,
+		UserAgent:  userAgent,
+	}, metacritic.NewMemoryCache(10))
+	c.TTL[metacritic.SearchPage] = time.Nanosecond
+
+	urls := []string{
+		"https://www.metacritic.com/search/game/Mario/results",
+		"https://www.metacritic.com/robots.txt",
+	}
+
+	for i := 0; i < 2; i++ {
+		for _, res := range c.CrawlWithContext(context.Background(), urls) {
+			if res.Cached {
+				t.Fatalf("CrawlWithContext() served '%s' from the cache", res.URL)
+			}
+			readBody(t, res)
+		}
+	}
+
+	if n := atomic.LoadInt32(requests); n != 4 {
+		t.Fatalf("%d requests were sent instead of 4", n)
+	}
+}
+
+func TestCachingCrawlerSkipsErrors(t *testing.T) {
+	t.Parallel()
+
+	cache := metacritic.NewMemoryCache(10)
+	mock := &MockClient{}
+	mock.DoFn = func(req *http.Request) (response *http.Response, err error) {
+		rec := httptest.NewRecorder()
+		rec.WriteHeader(http.StatusServiceUnavailable)
+		return rec.Result(), nil
+	}
+	c := metacritic.NewCachingCrawler(&metacritic.DefaultCrawler{
+		Client:     mock,
+		Concurrent: 1,
+		UserAgent:  userAgent,
+	}, cache)
+
+	res := c.CrawlOneWithContext(context.Background(), "https://www.metacritic.com/game/switch/super-mario-party")
+	if res.Error == nil {
+		t.Fatal("CrawlOneWithContext() did not return the error")
+	}
+
+	if cache.Len() != 0 {
+		t.Fatal("CrawlOneWithContext() cached an error")
+	}
+}
+
+func TestMetacritic_SearchCached(t *testing.T) {
+	t.Parallel()
+
+	var requests int32
+	counting := &MockClient{}
+	counting.DoFn = func(req *http.Request) (response *http.Response, err error) {
+		atomic.AddInt32(&requests, 1)
+		return mockClient.Do(req)
+	}
+
+	mc := buildWithClient(counting)
+	mc.Crawler = metacritic.NewCachingCrawler(mc.Crawler, metacritic.NewMemoryCache(10))
+
+	for i := 0; i < 2; i++ {
+		res, err := mc.Search("Mario", metacritic.Switch)
+		if err != nil {
+			t.Fatalf("Search() returned an error '%s'", err)
+		}
+
+		if len(res) != 2 {
+			t.Fatalf("Search() returned %d games instead of 2", len(res))
+		}
+	}
+
+	if n := atomic.LoadInt32(&requests); n != 3 {
+		t.Fatalf("%d requests were sent instead of 3", n)
+	}
+}
+
+func TestMemoryCacheEviction(t *testing.T) {
+	t.Parallel()
+
+	c := metacritic.NewMemoryCache(2)
+	c.Set("a", &metacritic.CacheEntry{})
+	c.Set("b", &metacritic.CacheEntry{})
+	c.Get("a")
+	c.Set("c", &metacritic.CacheEntry{})
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("the least recently used entry was not evicted")
+	}
+
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("a recently used entry was evicted")
+	}
+
+	c.Delete("a")
+	if c.Len() != 1 {
+		t.Fatalf("the cache has %d entries instead of 1", c.Len())
+	}
+}
+
+func TestFileCache(t *testing.T) {
+	t.Parallel()
+
+	dir, err := os.MkdirTemp("", "metacritic")
+	if err != nil {
+		t.Fatalf("error creating temp dir ('%s')", err)
+	}
+	defer os.RemoveAll(dir)
+
+	c, err := metacritic.NewFileCache(dir)
+	if err != nil {
+		t.Fatalf("NewFileCache() returned an error '%s'", err)
+	}
+
+	stored := time.Now().Round(0)
+	c.Set("https://www.metacritic.com/game/switch/super-mario-party", &metacritic.CacheEntry{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Etag": []string{`"abc"`}},
+		Body:       []byte("detail page"),
+		Stored:     stored,
+	})
+
+	entry, ok := c.Get("https://www.metacritic.com/game/switch/super-mario-party")
+	if !ok {
+		t.Fatal("Get() did not return the stored entry")
+	}
+
+	if string(entry.Body) != "detail page" || entry.Header.Get("ETag") != `"abc"` || !entry.Stored.Equal(stored) {
+		t.Fatalf("Get() returned a wrong entry '%+v'", entry)
+	}
+
+	c.Delete("https://www.metacritic.com/game/switch/super-mario-party")
+	if _, ok := c.Get("https://www.metacritic.com/game/switch/super-mario-party"); ok {
+		t.Fatal("Get() returned a deleted entry")
+	}
+}
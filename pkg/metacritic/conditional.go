@@ -0,0 +1,80 @@
+package metacritic
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+)
+
+// conditional adds If-None-Match and If-Modified-Since to req if the
+// ConditionalCache has a response for url with an ETag or Last-Modified header.
+//
+// It returns the cached entry or nil.
+func (c *DefaultCrawler) conditional(url string, req *http.Request) *CacheEntry {
+	entry, ok := c.ConditionalCache.Get(url)
+	if !ok {
+		return nil
+	}
+
+	etag := entry.Header.Get("ETag")
+	lastModified := entry.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return nil
+	}
+
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	return entry
+}
+
+// revalidated returns the response of the cached entry for a 304 response res
+// and marks the entry as fresh.
+func (c *DefaultCrawler) revalidated(url string, entry *CacheEntry, res *http.Response) *http.Response {
+	res.Body.Close()
+
+	updated := *entry
+	updated.Header = entry.Header.Clone()
+	for _, key := range []string{"ETag", "Last-Modified", "Cache-Control", "Expires"} {
+		if value := res.Header.Get(key); value != "" {
+			updated.Header.Set(key, value)
+		}
+	}
+	updated.Stored = time.Now()
+	c.ConditionalCache.Set(url, &updated)
+
+	response := updated.response()
+	response.Request = res.Request
+
+	return response
+}
+
+// storeValidated stores res in the ConditionalCache if it has an ETag or
+// Last-Modified header. The body of res is replaced with an in memory copy.
+func (c *DefaultCrawler) storeValidated(url string, res *http.Response) error {
+	if res.Header.Get("ETag") == "" && res.Header.Get("Last-Modified") == "" {
+		return nil
+	}
+
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	c.ConditionalCache.Set(url, &CacheEntry{
+		StatusCode: res.StatusCode,
+		Header:     res.Header.Clone(),
+		Body:       body,
+		Stored:     time.Now(),
+	})
+
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package metacritic_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stahlstift/go-metacritic/pkg/metacritic"
+)
+
+// validatingServer answers with an ETag and Last-Modified and returns 304 for matching conditional requests.
+func validatingServer(full *int32) *httptest.Server {
+	lastModified := time.Date(2019, time.December, 11, 0, 0, 0, 0, time.UTC).Format(http.TimeFormat)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/etag" && r.Header.Get("If-None-Match") == `"v1"` ||
+			r.URL.Path == "/modified" && r.Header.Get("If-Modified-Since") == lastModified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		atomic.AddInt32(full, 1)
+		if r.URL.Path == "/etag" {
+			w.Header().Set("ETag", `"v1"`)
+		} else {
+			w.Header().Set("Last-Modified", lastModified)
+		}
+		w.Write([]byte("detail page"))
+	}))
+}
+
+func TestConditionalRequests(t *testing.T) {
+	t.Parallel()
+
+	var full int32
+	server := validatingServer(&full)
+	defer server.Close()
+
+	c := &metacritic.DefaultCrawler{
+		Client:           server.Client(),
+		Concurrent:       2,
+		UserAgent:        userAgent,
+		ConditionalCache: metacritic.NewMemoryCache(10),
+	}
+
+	urls := []string{server.URL + "/etag", server.URL + "/modified"}
+	for i := 0; i < 2; i++ {
+		for _, res := range c.Crawl(urls) {
+			if res.Cached != (i == 1) {
+				t.Fatalf("Crawl() returned cached %t for '%s' in run %d", res.Cached, res.URL, i)
+			}
+
+			if body := readBody(t, res); body != "detail page" {
+				t.Fatalf("Crawl() returned the body '%s' for '%s'", body, res.URL)
+			}
+		}
+	}
+
+	if n := atomic.LoadInt32(&full); n != 2 {
+		t.Fatalf("%d full responses were sent instead of 2", n)
+	}
+}
+
+func TestConditionalRequestsWithCachingCrawler(t *testing.T) {
+	t.Parallel()
+
+	var full int32
+	server := validatingServer(&full)
+	defer server.Close()
+
+	cache := metacritic.NewMemoryCache(10)
+	c := metacritic.NewCachingCrawler(&metacritic.DefaultCrawler{
+		Client:           server.Client(),
+		Concurrent:       1,
+		UserAgent:        userAgent,
+		ConditionalCache: cache,
+	}, cache)
+	c.TTL[metacritic.OtherPage] = time.Nanosecond
+
+	for i := 0; i < 3; i++ {
+		res := c.CrawlOneWithContext(context.Background(), server.URL+"/etag")
+		if body := readBody(t, res); body != "detail page" {
+			t.Fatalf("CrawlOneWithContext() returned the body '%s'", body)
+		}
+	}
+
+	if n := atomic.LoadInt32(&full); n != 1 {
+		t.Fatalf("%d full responses were sent instead of 1", n)
+	}
+}
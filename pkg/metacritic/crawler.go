@@ -1,8 +1,14 @@
 package metacritic
 
 import (
+	"context"
+	"log/slog"
 	"net/http"
 	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Client is the interface used by the Crawler to retrieve the data from the url.
@@ -11,71 +17,298 @@ type Client interface {
 }
 
 // Result is the result returned from the DefaultCrawler.
+//
+// Responses with a non 2xx status code are returned as *HTTPStatusError without a Response.
 type Result struct {
 	Error    error
 	Response *http.Response
+	URL      string        // the requested url
+	FinalURL string        // the url of the response after following redirects
+	Latency  time.Duration // time until the response headers were received
+	Attempts int           // number of requests sent, including retries
+	Cached   bool          // the response was served from a cache
 }
 
 // DefaultCrawler is the default implementation for the Crawler interface.
+//
+// Failed requests are retried according to Retry - if Retry is nil every url is requested once.
+// Every request, including retries, waits for the RateLimiter if one is set.
+//
+// If ConditionalCache is set, responses with an ETag or Last-Modified header are
+// stored in it and requested again with If-None-Match and If-Modified-Since.
+// A 304 response is answered from the cache. The ConditionalCache may be shared
+// with a CachingCrawler wrapping the crawler, expired entries are then revalidated.
+//
+// If Headers is set, every request gets the headers of the chosen HeaderProfile,
+// otherwise only the UserAgent is set. A non-empty UserAgent always replaces the
+// User-Agent of the profile.
+//
+// Middleware wraps the Client for every request including robots.txt fetches,
+// the first middleware sees the request first.
+//
+// Observer is notified about every request, retry and revalidation.
+//
+// Logger receives a debug event for every request and a warning for every retry,
+// nothing is logged if it is nil.
+//
+// If Robots is set, urls disallowed for the UserAgent by the robots.txt of their
+// host are not requested but returned with ErrDisallowedByRobots. Without a
+// UserAgent only the rules for all robots apply, the User-Agent of a
+// HeaderProfile is never matched.
 type DefaultCrawler struct {
-	Concurrent int
-	Client     Client
-	UserAgent  string
+	Concurrent       int
+	Client           Client
+	UserAgent        string
+	Headers          HeaderProvider
+	Middleware       []Middleware
+	Retry            *RetryPolicy
+	RateLimiter      *RateLimiter
+	ConditionalCache Cache
+	Robots           *Robots
+	Observer         Observer
+	Logger           *slog.Logger
+}
+
+// query requests url in a span and retries it according to the RetryPolicy of the crawler.
+func (c *DefaultCrawler) query(ctx context.Context, url string) *Result {
+	ctx, span := spanTracer(ctx).Start(ctx, "metacritic.Crawl", trace.WithAttributes(
+		attribute.String("url.full", url),
+		attribute.String("metacritic.page_type", string(PageTypeOf(url))),
+	))
+
+	result := c.retry(ctx, url)
+	span.SetAttributes(resultAttributes(result)...)
+	endSpan(span, result.Error)
+
+	return result
 }
 
-func (c *DefaultCrawler) doQuery(url string) *Result {
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+// retry calls doQuery until it succeeds or the Retry policy gives up.
+func (c *DefaultCrawler) retry(ctx context.Context, url string) *Result {
+	for attempt := 1; ; attempt++ {
+		result := c.doQuery(ctx, url)
+		result.Attempts = attempt
+		c.logResult(ctx, result)
+
+		if result.Error == nil || c.Retry == nil || ctx.Err() != nil {
+			return result
+		}
+
+		delay, ok := c.Retry.delay(attempt, result.Error)
+		if !ok {
+			return result
+		}
+
+		loggerOrDiscard(c.Logger).WarnContext(ctx, "metacritic: retrying request",
+			slog.String("url", url),
+			slog.Int("attempt", attempt),
+			slog.Duration("delay", delay),
+			slog.Any("error", result.Error),
+		)
+
+		if sleep(ctx, delay) != nil {
+			return result
+		}
+
+		observerOrNop(c.Observer).Retried(PageTypeOf(url), result.Error)
+	}
+}
+
+func (c *DefaultCrawler) doQuery(ctx context.Context, url string) *Result {
+	result := &Result{
+		URL:      url,
+		FinalURL: url,
+	}
+
+	res, err := c.send(ctx, url, result)
 	if err != nil {
-		return &Result{
-			Error: err,
+		result.Error = err
+		return result
+	}
+
+	result.Response = res
+
+	return result
+}
+
+// setHeaders sets the headers of the HeaderProfile and the UserAgent.
+func (c *DefaultCrawler) setHeaders(req *http.Request) {
+	if c.Headers != nil {
+		for key, values := range c.Headers.Profile(req).Header {
+			req.Header[http.CanonicalHeaderKey(key)] = append([]string(nil), values...)
 		}
 	}
-	req.Header.Set("User-Agent", c.UserAgent)
 
-	res, err := c.Client.Do(req)
+	if c.UserAgent != "" || c.Headers == nil {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+}
+
+// send requests url once, setting the FinalURL, Latency and Cached of result.
+//
+// It returns the response only for 2xx status codes or answered from the ConditionalCache.
+func (c *DefaultCrawler) send(ctx context.Context, url string, result *Result) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return &Result{
-			Error: err,
+		return nil, err
+	}
+	c.setHeaders(req)
+
+	client := Chain(c.Client, c.Middleware...)
+
+	if c.Robots != nil {
+		if err := c.Robots.wait(ctx, client, c.UserAgent, req.URL); err != nil {
+			return nil, err
 		}
 	}
 
-	return &Result{
-		Response: res,
+	var cached *CacheEntry
+	if c.ConditionalCache != nil {
+		cached = c.conditional(url, req)
 	}
+
+	if c.RateLimiter != nil {
+		if err := c.RateLimiter.Wait(ctx, req.URL.Host); err != nil {
+			return nil, err
+		}
+	}
+
+	observer := observerOrNop(c.Observer)
+	pageType := PageTypeOf(url)
+	observer.RequestStarted(pageType)
+
+	start := time.Now()
+	res, err := client.Do(req)
+	result.Latency = time.Since(start)
+	if err != nil {
+		observer.RequestFinished(pageType, 0, result.Latency, err)
+		return nil, err
+	}
+	observer.RequestFinished(pageType, res.StatusCode, result.Latency, nil)
+
+	if res.Request != nil {
+		result.FinalURL = res.Request.URL.String()
+	}
+
+	if cached != nil && res.StatusCode == http.StatusNotModified {
+		result.Cached = true
+		observer.CacheHit(pageType)
+		return c.revalidated(url, cached, res), nil
+	}
+
+	if err := newHTTPStatusError(result.FinalURL, res); err != nil {
+		res.Body.Close()
+		return nil, err
+	}
+
+	if c.ConditionalCache != nil {
+		if err := c.storeValidated(url, res); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
 }
 
 // Crawl will start the crawling process for given urls in concurrent.
+//
+// The result at index i belongs to urls[i].
 func (c *DefaultCrawler) Crawl(urls []string) []*Result {
-	var mu sync.Mutex
+	return c.CrawlWithContext(context.Background(), urls)
+}
 
-	results := make([]*Result, 0, len(urls))
+// CrawlWithContext is like Crawl but aborts all in-flight requests when ctx is cancelled.
+//
+// Urls which were not requested before the cancellation get a Result with ctx.Err().
+func (c *DefaultCrawler) CrawlWithContext(ctx context.Context, urls []string) []*Result {
+	results := make([]*Result, len(urls))
 
+	var wg sync.WaitGroup
 	sem := make(chan struct{}, c.Concurrent)
-	for _, url := range urls {
-		sem <- struct{}{}
-		go func(u string) {
-			result := c.doQuery(u)
+	for i, url := range urls {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i] = &Result{Error: ctx.Err(), URL: url, FinalURL: url}
+			continue
+		}
 
-			mu.Lock()
-			defer mu.Unlock()
+		wg.Add(1)
+		go func(i int, u string) {
+			defer wg.Done()
 
-			results = append(results, result)
+			results[i] = c.query(ctx, u)
 
 			<-sem
-		}(url)
+		}(i, url)
 	}
 
-	for i := 0; i < c.Concurrent; i++ {
-		sem <- struct{}{}
-	}
+	wg.Wait()
 
 	return results
 }
 
 // CrawlOne calls Crawl returning the first element.
-//
-// Caution: Crawl is concurrent and is using a slice, so calling twice CrawlOne
-// could produce different results.
 func (c *DefaultCrawler) CrawlOne(url string) *Result {
-	return c.Crawl([]string{url})[0]
+	return c.CrawlOneWithContext(context.Background(), url)
+}
+
+// CrawlOneWithContext calls CrawlWithContext returning the first element.
+func (c *DefaultCrawler) CrawlOneWithContext(ctx context.Context, url string) *Result {
+	return c.CrawlWithContext(ctx, []string{url})[0]
+}
+
+// Stream crawls the urls received from urls in concurrent and sends every Result
+// to the returned channel as soon as it is done.
+//
+// The returned channel is unbuffered, so at most Concurrent responses are held
+// until they are received. It is closed once urls is closed and all results are
+// sent, or when ctx is done. The receiver has to close the response bodies.
+func (c *DefaultCrawler) Stream(ctx context.Context, urls <-chan string) <-chan *Result {
+	results := make(chan *Result)
+
+	workers := c.Concurrent
+	if workers < 1 {
+		workers = 1
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				var u string
+				var ok bool
+
+				select {
+				case <-ctx.Done():
+					return
+				case u, ok = <-urls:
+					if !ok {
+						return
+					}
+				}
+
+				result := c.query(ctx, u)
+
+				select {
+				case results <- result:
+				case <-ctx.Done():
+					if result.Response != nil {
+						result.Response.Body.Close()
+					}
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
 }
@@ -1,10 +1,16 @@
 package metacritic_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stahlstift/go-metacritic/pkg/metacritic"
 )
@@ -104,3 +110,212 @@ func TestCrawlOne(t *testing.T) {
 		t.Fatal("CrawlOne() did no returned a result")
 	}
 }
+
+func TestCrawlWithContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	mock := &MockClient{}
+	mock.DoFn = func(req *http.Request) (response *http.Response, err error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	}
+	c := &metacritic.DefaultCrawler{
+		Client:     mock,
+		Concurrent: 1,
+		UserAgent:  userAgent,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	res := c.CrawlWithContext(ctx, []string{"http://www.example.org", "http://www.example.org/1"})
+	if len(res) != 2 {
+		t.Fatalf("CrawlWithContext() returned %d results instead of 2", len(res))
+	}
+
+	for _, r := range res {
+		if r.Error != context.DeadlineExceeded {
+			t.Fatalf("CrawlWithContext() did not receive correct error. Expected '%s' - got '%s'", context.DeadlineExceeded, r.Error)
+		}
+	}
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestCrawlStatusCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		statusCode int
+		retryAfter string
+		min, max   time.Duration
+	}{
+		{http.StatusNotFound, "", 0, 0},
+		{http.StatusForbidden, "120", 0, 0},
+		{http.StatusTooManyRequests, "120", 120 * time.Second, 120 * time.Second},
+		{http.StatusServiceUnavailable, time.Now().Add(2 * time.Minute).UTC().Format(http.TimeFormat), time.Minute, 2 * time.Minute},
+		{http.StatusServiceUnavailable, "invalid", 0, 0},
+	}
+
+	for _, test := range tests {
+		body := &closeRecorder{Reader: strings.NewReader("error page")}
+
+		mock := &MockClient{}
+		mock.DoFn = func(req *http.Request) (response *http.Response, err error) {
+			rec := httptest.NewRecorder()
+			if test.retryAfter != "" {
+				rec.Header().Set("Retry-After", test.retryAfter)
+			}
+			rec.WriteHeader(test.statusCode)
+
+			res := rec.Result()
+			res.Body = body
+			return res, nil
+		}
+		c := &metacritic.DefaultCrawler{
+			Client:     mock,
+			Concurrent: 1,
+			UserAgent:  userAgent,
+		}
+
+		res := c.CrawlOne("http://www.example.org")
+
+		var statusErr *metacritic.HTTPStatusError
+		if !errors.As(res.Error, &statusErr) {
+			t.Fatalf("CrawlOne() returned '%v' instead of a HTTPStatusError", res.Error)
+		}
+
+		if statusErr.StatusCode != test.statusCode || statusErr.URL != "http://www.example.org" {
+			t.Fatalf("CrawlOne() returned a wrong HTTPStatusError '%s'", statusErr)
+		}
+
+		if statusErr.RetryAfter < test.min || statusErr.RetryAfter > test.max {
+			t.Fatalf("CrawlOne() returned a wrong Retry-After '%s' for '%s'", statusErr.RetryAfter, test.retryAfter)
+		}
+
+		if res.Response != nil || !body.closed {
+			t.Fatalf("CrawlOne() returned the response of status %d", test.statusCode)
+		}
+	}
+}
+
+func TestCrawlPreservesOrder(t *testing.T) {
+	t.Parallel()
+
+	mock := &MockClient{}
+	mock.DoFn = func(req *http.Request) (response *http.Response, err error) {
+		// the first url finishes last
+		delay, _ := strconv.Atoi(strings.TrimPrefix(req.URL.Path, "/"))
+		time.Sleep(time.Duration(delay) * time.Millisecond)
+		return httptest.NewRecorder().Result(), nil
+	}
+	c := &metacritic.DefaultCrawler{
+		Client:     mock,
+		Concurrent: 4,
+		UserAgent:  userAgent,
+	}
+
+	urls := []string{"http://www.example.org/40", "http://www.example.org/30", "http://www.example.org/20", "http://www.example.org/10"}
+	res := c.Crawl(urls)
+	for i, r := range res {
+		if r.URL != urls[i] {
+			t.Fatalf("Crawl() returned the result of '%s' at index %d instead of '%s'", r.URL, i, urls[i])
+		}
+	}
+}
+
+func TestCrawlFinalURL(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+		}
+	}))
+	defer server.Close()
+
+	c := &metacritic.DefaultCrawler{
+		Client:     server.Client(),
+		Concurrent: 1,
+		UserAgent:  userAgent,
+	}
+
+	res := c.CrawlOne(server.URL + "/old")
+	if res.Error != nil {
+		t.Fatalf("CrawlOne() returned an error '%s'", res.Error)
+	}
+	res.Response.Body.Close()
+
+	if res.URL != server.URL+"/old" || res.FinalURL != server.URL+"/new" {
+		t.Fatalf("CrawlOne() returned url '%s' and final url '%s'", res.URL, res.FinalURL)
+	}
+}
+
+func TestStream(t *testing.T) {
+	t.Parallel()
+
+	c := &metacritic.DefaultCrawler{
+		Client:     &MockClient{},
+		Concurrent: 3,
+		UserAgent:  userAgent,
+	}
+
+	urls := make(chan string)
+	go func() {
+		defer close(urls)
+		for i := 0; i < 10; i++ {
+			urls <- "http://www.example.org/" + strconv.Itoa(i)
+		}
+	}()
+
+	seen := make(map[string]bool)
+	for r := range c.Stream(context.Background(), urls) {
+		if r.Error != nil {
+			t.Fatalf("Stream() returned an error '%s'", r.Error)
+		}
+		r.Response.Body.Close()
+		seen[r.URL] = true
+	}
+
+	if len(seen) != 10 {
+		t.Fatalf("Stream() returned %d results instead of 10", len(seen))
+	}
+}
+
+func TestStreamContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	c := &metacritic.DefaultCrawler{
+		Client:     &MockClient{},
+		Concurrent: 2,
+		UserAgent:  userAgent,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// urls is never closed, so only the cancellation can end the stream
+	urls := make(chan string, 1)
+	urls <- "http://www.example.org"
+
+	results := c.Stream(ctx, urls)
+	r := <-results
+	r.Response.Body.Close()
+	cancel()
+
+	select {
+	case _, ok := <-results:
+		if ok {
+			t.Fatal("Stream() returned a result after the cancellation")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Stream() did not close the results after the cancellation")
+	}
+}
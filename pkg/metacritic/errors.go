@@ -0,0 +1,112 @@
+package metacritic
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned if metacritic has no page or no result for the request.
+	ErrNotFound = errors.New("metacritic: not found")
+
+	// ErrLayoutChanged is returned if a page does not contain the expected data,
+	// which usually means metacritic changed its html.
+	ErrLayoutChanged = errors.New("metacritic: layout changed")
+
+	// ErrRateLimited is returned if metacritic rejects requests because too many were sent.
+	ErrRateLimited = errors.New("metacritic: rate limited")
+
+	// ErrDisallowedByRobots is returned for urls the robots.txt of the host disallows.
+	ErrDisallowedByRobots = errors.New("metacritic: disallowed by robots.txt")
+
+	// ErrCircuitOpen is returned for urls not crawled because a CircuitBreaker is open.
+	ErrCircuitOpen = errors.New("metacritic: circuit open")
+
+	// ErrUnsupported is returned if the Parser of Metacritic cannot parse the requested data.
+	ErrUnsupported = errors.New("metacritic: unsupported by parser")
+)
+
+// HTTPStatusError is returned if metacritic answers with a non 2xx status code.
+//
+// errors.Is reports ErrNotFound for 404 and 410 and ErrRateLimited for 429.
+type HTTPStatusError struct {
+	StatusCode int
+	URL        string
+	RetryAfter time.Duration // parsed Retry-After header of 429 and 503 responses
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("metacritic: %s returned status %d", e.URL, e.StatusCode)
+}
+
+// Is makes HTTPStatusError usable with errors.Is.
+func (e *HTTPStatusError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound || e.StatusCode == http.StatusGone
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	}
+
+	return false
+}
+
+// newHTTPStatusError returns a *HTTPStatusError for res or nil if res has a 2xx status code.
+func newHTTPStatusError(url string, res *http.Response) *HTTPStatusError {
+	if res.StatusCode >= 200 && res.StatusCode <= 299 {
+		return nil
+	}
+
+	err := &HTTPStatusError{
+		StatusCode: res.StatusCode,
+		URL:        url,
+	}
+
+	if res.StatusCode == http.StatusTooManyRequests || res.StatusCode == http.StatusServiceUnavailable {
+		err.RetryAfter = parseRetryAfter(res.Header.Get("Retry-After"), time.Now())
+	}
+
+	return err
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either
+// a number of seconds or a http date. It returns 0 for invalid values.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil || date.Before(now) {
+		return 0
+	}
+
+	return date.Sub(now)
+}
+
+// resultError returns the error of result or a *HTTPStatusError if the response
+// has a non 2xx status code.
+//
+// The body of the response is closed if an error is returned.
+func resultError(result *Result, url string) error {
+	if result == nil {
+		return fmt.Errorf("no result for %s", url)
+	}
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if err := newHTTPStatusError(url, result.Response); err != nil {
+		result.Response.Body.Close()
+		return err
+	}
+
+	return nil
+}
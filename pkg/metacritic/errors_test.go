@@ -0,0 +1,33 @@
+package metacritic_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stahlstift/go-metacritic/pkg/metacritic"
+)
+
+func TestHTTPStatusError_Is(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		statusCode int
+		target     error
+		is         bool
+	}{
+		{http.StatusNotFound, metacritic.ErrNotFound, true},
+		{http.StatusGone, metacritic.ErrNotFound, true},
+		{http.StatusTooManyRequests, metacritic.ErrRateLimited, true},
+		{http.StatusTooManyRequests, metacritic.ErrNotFound, false},
+		{http.StatusInternalServerError, metacritic.ErrRateLimited, false},
+	}
+
+	for _, test := range tests {
+		err := fmt.Errorf("wrapped: %w", &metacritic.HTTPStatusError{StatusCode: test.statusCode})
+		if errors.Is(err, test.target) != test.is {
+			t.Errorf("errors.Is(%d, '%s') did not return %t", test.statusCode, test.target, test.is)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package metacritic
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// HeaderProfile is a consistent set of request headers of a browser,
+// including User-Agent, Accept and Accept-Language.
+type HeaderProfile struct {
+	Name   string
+	Header http.Header
+}
+
+// HeaderProvider chooses the HeaderProfile for every request of a DefaultCrawler.
+//
+// Implementations must be safe for concurrent use.
+type HeaderProvider interface {
+	Profile(req *http.Request) HeaderProfile
+}
+
+const (
+	acceptChrome  = "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8"
+	acceptFirefox = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+)
+
+// DefaultHeaderProfiles are the profiles used by NewRotatingHeaders if none are given.
+var DefaultHeaderProfiles = []HeaderProfile{
+	{
+		Name: "Chrome 141 Windows",
+		Header: http.Header{
+			"User-Agent": {"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 " +
+				"(KHTML, like Gecko) Chrome/141.0.0.0 Safari/537.36"},
+			"Accept":             {acceptChrome},
+			"Accept-Language":    {"en-US,en;q=0.9"},
+			"Sec-Ch-Ua":          {`"Google Chrome";v="141", "Not?A_Brand";v="8", "Chromium";v="141"`},
+			"Sec-Ch-Ua-Mobile":   {"?0"},
+			"Sec-Ch-Ua-Platform": {`"Windows"`},
+		},
+	},
+	{
+		Name: "Chrome 141 macOS",
+		Header: http.Header{
+			"User-Agent": {"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 " +
+				"(KHTML, like Gecko) Chrome/141.0.0.0 Safari/537.36"},
+			"Accept":             {acceptChrome},
+			"Accept-Language":    {"en-US,en;q=0.9"},
+			"Sec-Ch-Ua":          {`"Google Chrome";v="141", "Not?A_Brand";v="8", "Chromium";v="141"`},
+			"Sec-Ch-Ua-Mobile":   {"?0"},
+			"Sec-Ch-Ua-Platform": {`"macOS"`},
+		},
+	},
+	{
+		Name: "Edge 141 Windows",
+		Header: http.Header{
+			"User-Agent": {"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 " +
+				"(KHTML, like Gecko) Chrome/141.0.0.0 Safari/537.36 Edg/141.0.0.0"},
+			"Accept":             {acceptChrome},
+			"Accept-Language":    {"en-US,en;q=0.9"},
+			"Sec-Ch-Ua":          {`"Microsoft Edge";v="141", "Not?A_Brand";v="8", "Chromium";v="141"`},
+			"Sec-Ch-Ua-Mobile":   {"?0"},
+			"Sec-Ch-Ua-Platform": {`"Windows"`},
+		},
+	},
+	{
+		Name: "Firefox 143 Windows",
+		Header: http.Header{
+			"User-Agent":      {"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:143.0) Gecko/20100101 Firefox/143.0"},
+			"Accept":          {acceptFirefox},
+			"Accept-Language": {"en-US,en;q=0.5"},
+		},
+	},
+	{
+		Name: "Safari 26 macOS",
+		Header: http.Header{
+			"User-Agent": {"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 " +
+				"(KHTML, like Gecko) Version/26.0 Safari/605.1.15"},
+			"Accept":          {acceptFirefox},
+			"Accept-Language": {"en-US,en;q=0.9"},
+		},
+	},
+}
+
+// RotatingHeaders is a HeaderProvider using its Profiles round robin.
+type RotatingHeaders struct {
+	Profiles []HeaderProfile
+
+	n uint32
+}
+
+// NewRotatingHeaders returns a RotatingHeaders for profiles or DefaultHeaderProfiles if none are given.
+func NewRotatingHeaders(profiles ...HeaderProfile) *RotatingHeaders {
+	if len(profiles) == 0 {
+		profiles = DefaultHeaderProfiles
+	}
+
+	return &RotatingHeaders{Profiles: profiles}
+}
+
+// Profile returns the next profile, using DefaultHeaderProfiles if Profiles is empty.
+func (r *RotatingHeaders) Profile(req *http.Request) HeaderProfile {
+	profiles := r.Profiles
+	if len(profiles) == 0 {
+		profiles = DefaultHeaderProfiles
+	}
+
+	n := atomic.AddUint32(&r.n, 1) - 1
+	return profiles[n%uint32(len(profiles))]
+}
@@ -0,0 +1,108 @@
+package metacritic_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stahlstift/go-metacritic/pkg/metacritic"
+)
+
+func TestRotatingHeaders(t *testing.T) {
+	t.Parallel()
+
+	a := metacritic.HeaderProfile{Name: "a", Header: http.Header{"User-Agent": {"a"}}}
+	b := metacritic.HeaderProfile{Name: "b", Header: http.Header{"User-Agent": {"b"}}}
+	r := metacritic.NewRotatingHeaders(a, b)
+
+	for i, want := range []string{"a", "b", "a"} {
+		if got := r.Profile(nil).Name; got != want {
+			t.Fatalf("Profile() #%d returned '%s' instead of '%s'", i, got, want)
+		}
+	}
+}
+
+func TestRotatingHeadersZeroValue(t *testing.T) {
+	t.Parallel()
+
+	r := &metacritic.RotatingHeaders{}
+	if got := r.Profile(nil).Name; got != metacritic.DefaultHeaderProfiles[0].Name {
+		t.Fatalf("Profile() returned '%s' instead of the first default profile", got)
+	}
+}
+
+func TestDefaultHeaderProfiles(t *testing.T) {
+	t.Parallel()
+
+	r := metacritic.NewRotatingHeaders()
+	if len(r.Profiles) != len(metacritic.DefaultHeaderProfiles) {
+		t.Fatalf("NewRotatingHeaders() did not use DefaultHeaderProfiles")
+	}
+
+	for _, p := range metacritic.DefaultHeaderProfiles {
+		for _, key := range []string{"User-Agent", "Accept", "Accept-Language"} {
+			if p.Header.Get(key) == "" {
+				t.Fatalf("profile '%s' has no %s", p.Name, key)
+			}
+		}
+	}
+}
+
+func TestCrawlerSetsProfileHeaders(t *testing.T) {
+	t.Parallel()
+
+	profiles := []metacritic.HeaderProfile{
+		{Name: "a", Header: http.Header{"User-Agent": {"a"}, "Accept-Language": {"de"}}},
+		{Name: "b", Header: http.Header{"User-Agent": {"b"}, "Accept-Language": {"fr"}}},
+	}
+
+	var mu sync.Mutex
+	seen := map[string]string{}
+	mock := &MockClient{}
+	mock.DoFn = func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		seen[req.Header.Get("User-Agent")] = req.Header.Get("Accept-Language")
+		mu.Unlock()
+		return httptest.NewRecorder().Result(), nil
+	}
+	c := &metacritic.DefaultCrawler{
+		Client:     mock,
+		Concurrent: 1,
+		Headers:    metacritic.NewRotatingHeaders(profiles...),
+	}
+	_ = c.Crawl([]string{"http://www.example.org/1", "http://www.example.org/2"})
+
+	if len(seen) != 2 || seen["a"] != "de" || seen["b"] != "fr" {
+		t.Fatalf("Crawl() sent headers %v instead of both profiles", seen)
+	}
+}
+
+func TestCrawlerUserAgentOverridesProfile(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var sent []http.Header
+	mock := &MockClient{}
+	mock.DoFn = func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		sent = append(sent, req.Header.Clone())
+		mu.Unlock()
+		return httptest.NewRecorder().Result(), nil
+	}
+
+	c := metacritic.New().Crawler.(*metacritic.DefaultCrawler)
+	c.Client = mock
+	c.RateLimiter = nil
+	c.UserAgent = "mybot"
+	_ = c.Crawl([]string{"http://www.example.org/1", "http://www.example.org/2"})
+
+	if len(sent) != 2 {
+		t.Fatalf("Crawl() sent %d requests instead of 2", len(sent))
+	}
+	for _, header := range sent {
+		if header.Get("User-Agent") != "mybot" || header.Get("Accept") == "" {
+			t.Fatalf("Crawl() sent '%s' with Accept '%s' instead of the UserAgent and the profile headers", header.Get("User-Agent"), header.Get("Accept"))
+		}
+	}
+}
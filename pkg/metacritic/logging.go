@@ -0,0 +1,79 @@
+package metacritic
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// discardLogger is used if no Logger is set.
+var discardLogger = slog.New(discardHandler{})
+
+// discardHandler is a slog.Handler which drops every record.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+// loggerOrDiscard returns l or a logger discarding everything if l is nil.
+func loggerOrDiscard(l *slog.Logger) *slog.Logger {
+	if l == nil {
+		return discardLogger
+	}
+
+	return l
+}
+
+// logResult logs a single attempt of a request at debug level.
+func (c *DefaultCrawler) logResult(ctx context.Context, result *Result) {
+	logger := loggerOrDiscard(c.Logger)
+	if !logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("url", result.URL),
+		slog.Int("attempt", result.Attempts),
+		slog.Duration("latency", result.Latency),
+		slog.Bool("cached", result.Cached),
+	}
+	if result.FinalURL != result.URL {
+		attrs = append(attrs, slog.String("final_url", result.FinalURL))
+	}
+
+	var statusErr *HTTPStatusError
+	switch {
+	case result.Response != nil:
+		attrs = append(attrs, slog.Int("status", result.Response.StatusCode))
+	case errors.As(result.Error, &statusErr):
+		attrs = append(attrs, slog.Int("status", statusErr.StatusCode))
+	}
+	if result.Error != nil {
+		attrs = append(attrs, slog.Any("error", result.Error))
+	}
+
+	logger.LogAttrs(ctx, slog.LevelDebug, "metacritic: request", attrs...)
+}
+
+// logFallbacks logs the fields of game the parser could not find at debug level.
+func (m *Metacritic) logFallbacks(ctx context.Context, url string, game *Game) {
+	var missing []string
+	if !game.HasMetaScore {
+		missing = append(missing, "metascore")
+	}
+	if !game.HasUserScore {
+		missing = append(missing, "userscore")
+	}
+	if game.ReleaseDate.IsZero() {
+		missing = append(missing, "release_date")
+	}
+
+	if len(missing) > 0 {
+		loggerOrDiscard(m.Logger).DebugContext(ctx, "metacritic: parser fallback",
+			slog.String("url", url),
+			slog.Any("missing", missing),
+		)
+	}
+}
@@ -0,0 +1,87 @@
+package metacritic_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stahlstift/go-metacritic/pkg/metacritic"
+)
+
+// countMessages returns how often msg was logged to the JSON log buf.
+func countMessages(buf *bytes.Buffer, msg string) int {
+	return strings.Count(buf.String(), `"msg":"`+msg+`"`)
+}
+
+func TestMetacritic_SearchLogs(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	client := &MockClient{}
+	client.DoFn = func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/super-mario-party") {
+			rec := httptest.NewRecorder()
+			rec.WriteHeader(http.StatusNotFound)
+			return rec.Result(), nil
+		}
+		return mockClient.Do(req)
+	}
+
+	mc := buildWithClient(client)
+	mc.Logger = logger
+	mc.Crawler.(*metacritic.DefaultCrawler).Logger = logger
+
+	_, report, err := mc.SearchWithReport(context.Background(), "Mario", metacritic.Switch)
+	if err != nil {
+		t.Fatalf("SearchWithReport() returned an error '%s'", err)
+	}
+
+	if n := countMessages(&buf, "metacritic: search"); n != 1 {
+		t.Fatalf("SearchWithReport() logged %d search events instead of 1", n)
+	}
+	if n := countMessages(&buf, "metacritic: request"); n != len(report.Pages) {
+		t.Fatalf("SearchWithReport() logged %d requests instead of %d", n, len(report.Pages))
+	}
+	if n := countMessages(&buf, "metacritic: dropped detail page"); n != 1 || len(report.Failed()) != 1 {
+		t.Fatalf("SearchWithReport() logged %d dropped pages instead of 1", n)
+	}
+	if !strings.Contains(buf.String(), `"level":"WARN"`) {
+		t.Fatalf("SearchWithReport() did not log the dropped page as warning")
+	}
+}
+
+func TestRetryLogs(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	client, _ := failingClient(2, http.StatusServiceUnavailable, nil)
+	c := retryCrawler(client)
+	c.Logger = slog.New(slog.NewJSONHandler(&buf, nil))
+
+	result := c.CrawlOne("http://www.example.org")
+	if result.Error != nil {
+		t.Fatalf("CrawlOne() returned an error '%s'", result.Error)
+	}
+
+	if n := countMessages(&buf, "metacritic: retrying request"); n != 2 {
+		t.Fatalf("CrawlOne() logged %d retries instead of 2", n)
+	}
+	if n := countMessages(&buf, "metacritic: request"); n != 0 {
+		t.Fatalf("CrawlOne() logged %d debug events at info level", n)
+	}
+}
+
+func TestNilLogger(t *testing.T) {
+	t.Parallel()
+
+	mc := buildWithClient(mockClient)
+	if _, err := mc.Search("Mario", metacritic.Switch); err != nil {
+		t.Fatalf("Search() returned an error '%s'", err)
+	}
+}
@@ -1,39 +1,177 @@
 package metacritic
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/hbakhtiyor/strsim"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Game represents the result from metacritic.
+//
+// MetaScore and UserScore are 0 if metacritic shows no score ("tbd") -
+// use HasMetaScore and HasUserScore to tell them apart from a real zero.
 type Game struct {
 	Link      string
 	MetaScore uint8
 	Title     string
 	UserScore float32
+
+	HasMetaScore      bool
+	HasUserScore      bool
+	CriticReviewCount int
+	UserRatingCount   int
+
+	CriticDistribution ScoreDistribution
+	UserDistribution   ScoreDistribution
+
+	Description   string
+	ReleaseDate   time.Time
+	Image         string
+	ContentRating string // e.g. "ESRB E"
+	Platform      string
+	Genres        []string
+	Publishers    []Organization
+	Credits       []Person
+	Trailer       *Video
+}
+
+// Organization is a company listed on metacritic, like a publisher.
+type Organization struct {
+	Name string
+	URL  string
+}
+
+// Person is a person credited for a game.
+type Person struct {
+	Name string
+	URL  string
+}
+
+// Video is a trailer of a game.
+type Video struct {
+	Name         string
+	Description  string
+	ThumbnailURL string
+	UploadDate   time.Time
 }
 
 // Crawler is the interface used by the Metacritic struct to retrieve the data.
+//
+// Implementations must return one Result per url in the order of urls.
 type Crawler interface {
 	Crawl(urls []string) []*Result
 	CrawlOne(url string) *Result
 }
 
+// ContextCrawler is a Crawler which aborts its in-flight requests when ctx is cancelled.
+//
+// Metacritic uses the context variants if its Crawler implements them.
+type ContextCrawler interface {
+	Crawler
+	CrawlWithContext(ctx context.Context, urls []string) []*Result
+	CrawlOneWithContext(ctx context.Context, url string) *Result
+}
+
+// crawl crawls urls with c, passing ctx if c is a ContextCrawler.
+func crawl(ctx context.Context, c Crawler, urls []string) []*Result {
+	if cc, ok := c.(ContextCrawler); ok {
+		return cc.CrawlWithContext(ctx, urls)
+	}
+
+	return c.Crawl(urls)
+}
+
+// crawlOne crawls url with c, passing ctx if c is a ContextCrawler.
+func crawlOne(ctx context.Context, c Crawler, url string) *Result {
+	if cc, ok := c.(ContextCrawler); ok {
+		return cc.CrawlOneWithContext(ctx, url)
+	}
+
+	return c.CrawlOne(url)
+}
+
+// Parser is the interface used by the Metacritic struct to extract the data from the crawled pages.
+//
+// Further capabilities are optional, see GameParser, SearchPager, SearchHitParser,
+// CriticReviewParser and UserReviewParser.
 type Parser interface {
 	Game(body io.Reader) *Game
 	Search(body io.Reader) []string
 }
 
+// SearchPager is a Parser which knows how many pages the search results are spread over.
+//
+// Metacritic only crawls the first search result page if its Parser does not implement it.
+type SearchPager interface {
+	SearchPages(body io.Reader) int
+}
+
+// GameParser is a Parser reporting why a game detail page cannot be parsed.
+//
+// Metacritic uses ParseGame if its Parser implements it.
+type GameParser interface {
+	ParseGame(body io.Reader) (*Game, error)
+}
+
+// parseGameBody parses the game detail page body with p.
+func parseGameBody(p Parser, body io.Reader) (*Game, error) {
+	if gp, ok := p.(GameParser); ok {
+		return gp.ParseGame(body)
+	}
+
+	game := p.Game(body)
+	if game == nil {
+		return nil, fmt.Errorf("%w: no game found", ErrLayoutChanged)
+	}
+
+	return game, nil
+}
+
+// parseSearchBody returns the urls found on the search result page body with p
+// and the number of pages, which is 1 if p is no SearchPager.
+func parseSearchBody(p Parser, body io.Reader) ([]string, int) {
+	sp, ok := p.(SearchPager)
+	if !ok {
+		return p.Search(body), 1
+	}
+
+	// both parse the whole page, a read error leaves them with what was read
+	b, _ := io.ReadAll(body)
+
+	return p.Search(bytes.NewReader(b)), sp.SearchPages(bytes.NewReader(b))
+}
+
 // Metacritic is the main service to get the details for a game.
+//
+// Searches are traced with OpenTelemetry spans of the TracerProvider or the
+// global one if none is set. The DefaultCrawler adds a child span per crawled
+// url using the provider of the span in the context.
+//
+// Logger receives a warning for every dropped detail page and a debug event
+// for every game with missing data, nothing is logged if it is nil.
+//
+// MaxPages is the number of search result pages a search follows, zero means
+// only the first page. MaxResults limits the number of detail pages crawled by
+// a search, zero means no limit.
 type Metacritic struct {
-	Crawler Crawler
-	Parser  Parser
+	Crawler        Crawler
+	Parser         Parser
+	TracerProvider trace.TracerProvider
+	Logger         *slog.Logger
+	MaxPages       int
+	MaxResults     int
 }
 
 // New returns a new Metacritic given a Client, concurrent and useragent.
@@ -42,64 +180,211 @@ type Metacritic struct {
 func New() *Metacritic {
 	return &Metacritic{
 		Crawler: &DefaultCrawler{
-			Client:     &http.Client{Timeout: time.Second * 10},
-			Concurrent: 3,
-			UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 " +
-				"(KHTML, like Gecko) Chrome/74.0.3729.169 Safari/537.36",
+			Client:      &http.Client{Timeout: time.Second * 10},
+			Concurrent:  3,
+			Headers:     NewRotatingHeaders(),
+			Retry:       DefaultRetryPolicy(),
+			RateLimiter: NewRateLimiter(2, 3),
 		},
 		Parser: &DefaultParser{},
 	}
 }
 
-// startSearch will start the crawling process of metacritic.
+// searchPageURL returns the url of the search result page of title and platform.
 //
-// It will call the search page with title and platform crawling for all the detail pages.
-// Then it will crawl every detail page in concurrent to extract the scores.
-func (m *Metacritic) startSearch(title string, platform Platform) ([]*Game, error) {
-	var retVal []*Game
-
-	result := m.Crawler.CrawlOne(fmt.Sprintf(
+// page is zero based like the page query parameter of metacritic.
+func searchPageURL(title string, platform Platform, page int) string {
+	u := fmt.Sprintf(
 		`https://www.metacritic.com/search/game/%s/results?plats[%s]=1&search_type=advanced`,
 		url.PathEscape(title),
 		platform,
+	)
+	if page > 0 {
+		u += "&page=" + strconv.Itoa(page)
+	}
+
+	return u
+}
+
+// startSearch will start the crawling process of metacritic.
+//
+// It will call the search pages with title and platform crawling for all the detail pages.
+// Then it will crawl every detail page in concurrent to extract the scores.
+// If ctx is cancelled it returns ctx.Err() as soon as the running requests are aborted.
+func (m *Metacritic) startSearch(ctx context.Context, title string, platform Platform) (games []*Game, report *SearchReport, err error) {
+	ctx, span := m.tracer().Start(ctx, "metacritic.Search", trace.WithAttributes(
+		attribute.String("metacritic.title", title),
+		attribute.String("metacritic.platform", string(platform)),
 	))
-	if result == nil || result.Error != nil {
-		return retVal, fmt.Errorf("cannot crawl search result page")
+	defer func() {
+		span.SetAttributes(attribute.Int("metacritic.games", len(games)))
+		endSpan(span, err)
+	}()
+
+	report = &SearchReport{}
+
+	urls, err := m.searchURLs(ctx, title, platform, report)
+	if err != nil {
+		return nil, report, err
 	}
 
-	defer result.Response.Body.Close()
-	urls := m.Parser.Search(result.Response.Body)
+	loggerOrDiscard(m.Logger).DebugContext(ctx, "metacritic: search",
+		slog.String("title", title),
+		slog.String("platform", string(platform)),
+		slog.Int("results", len(urls)),
+	)
+
+	games = m.crawlGames(ctx, urls, report)
+	if err := ctx.Err(); err != nil {
+		return nil, report, err
+	}
+
+	return games, report, nil
+}
+
+// searchURLs crawls the search result pages of title and platform and returns
+// the detail page urls without duplicates, at most MaxResults.
+func (m *Metacritic) searchURLs(ctx context.Context, title string, platform Platform, report *SearchReport) ([]string, error) {
+	var urls []string
+	seen := make(map[string]bool)
+	err := m.searchPages(ctx, title, platform, report, func(body io.Reader) (int, int) {
+		found, pages := parseSearchBody(m.Parser, body)
+		for _, u := range found {
+			if !seen[u] {
+				seen[u] = true
+				urls = append(urls, u)
+			}
+		}
+		return len(urls), pages
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if m.MaxResults > 0 && len(urls) > m.MaxResults {
+		urls = urls[:m.MaxResults]
+	}
+
+	return urls, nil
+}
+
+// searchPages crawls the search result pages of title and platform one after
+// another up to MaxPages or until MaxResults are found, calling parse with the
+// body of every page. parse returns the number of results found so far and
+// the number of pages.
+//
+// Only a failed first page is an error, later pages which fail end the crawl.
+func (m *Metacritic) searchPages(ctx context.Context, title string, platform Platform, report *SearchReport, parse func(body io.Reader) (int, int)) error {
+	maxPages := m.MaxPages
+	if maxPages < 1 {
+		maxPages = 1
+	}
+
+	for page, pages, results := 0, 1, 0; page < pages && page < maxPages; page++ {
+		if m.MaxResults > 0 && results >= m.MaxResults {
+			break
+		}
+
+		pageURL := searchPageURL(title, platform, page)
+		result := crawlOne(ctx, m.Crawler, pageURL)
+		if err := ctx.Err(); err != nil {
+			if result != nil && result.Error == nil {
+				result.Response.Body.Close()
+			}
+			return err
+		}
+
+		err := resultError(result, pageURL)
+		report.add(newPageReport(pageURL, result, err))
+		if err != nil {
+			if page == 0 {
+				return fmt.Errorf("cannot crawl search result page: %w", err)
+			}
+			break
+		}
+
+		_, span := spanTracer(ctx).Start(ctx, "metacritic.ParseSearch", trace.WithAttributes(
+			attribute.String("url.full", pageURL),
+		))
+		results, pages = parse(result.Response.Body)
+		result.Response.Body.Close()
+		span.SetAttributes(
+			attribute.Int("metacritic.results", results),
+			attribute.Int("metacritic.pages", pages),
+		)
+		span.End()
+	}
+
+	return nil
+}
+
+// crawlGames crawls every detail page of urls in concurrent and adds their outcome to report.
+//
+// The games and the reports are in the order of urls.
+func (m *Metacritic) crawlGames(ctx context.Context, urls []string, report *SearchReport) []*Game {
+	results := crawl(ctx, m.Crawler, urls)
+	games := make([]*Game, len(results))
+	pages := make([]PageReport, len(results))
 
-	var mu sync.Mutex
 	var wg sync.WaitGroup
-	for _, g := range m.Crawler.Crawl(urls) {
+	for i, g := range results {
 		wg.Add(1)
-		go func(g *Result) {
+		go func(i int, g *Result) {
 			defer wg.Done()
 
-			if g == nil || g.Error != nil {
-				return
+			u := urls[i]
+			game, err := m.parseGame(ctx, g, u)
+			if err != nil && ctx.Err() == nil {
+				loggerOrDiscard(m.Logger).WarnContext(ctx, "metacritic: dropped detail page",
+					slog.String("url", u),
+					slog.Any("error", err),
+				)
 			}
+			pages[i] = newPageReport(u, g, err)
+			games[i] = game
+		}(i, g)
+	}
 
-			defer g.Response.Body.Close()
-			game := m.Parser.Game(g.Response.Body)
-			if game == nil {
-				return
-			}
+	wg.Wait()
 
-			mu.Lock()
-			defer mu.Unlock()
+	var retVal []*Game
+	for i, game := range games {
+		report.add(pages[i])
+		if game != nil {
 			retVal = append(retVal, game)
-		}(g)
+		}
 	}
 
-	wg.Wait()
+	return retVal
+}
+
+// parseGame parses the detail page url crawled with result.
+func (m *Metacritic) parseGame(ctx context.Context, result *Result, url string) (*Game, error) {
+	if err := resultError(result, url); err != nil {
+		return nil, err
+	}
+
+	defer result.Response.Body.Close()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	_, span := spanTracer(ctx).Start(ctx, "metacritic.ParseGame", trace.WithAttributes(
+		attribute.String("url.full", url),
+	))
+	game, err := parseGameBody(m.Parser, result.Response.Body)
+	endSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
 
-	return retVal, nil
+	m.logFallbacks(ctx, url, game)
+
+	return game, nil
 }
 
-// findBestMatch returns the best match for title for the given games.
-func (m *Metacritic) findBestMatch(title string, games []*Game) *Game {
+// bestMatch returns the best match for title for the given games.
+func (m *Metacritic) bestMatch(title string, games []*Game) *Game {
 	l := len(games)
 	if l == 0 {
 		return nil
@@ -126,7 +411,40 @@ func (m *Metacritic) findBestMatch(title string, games []*Game) *Game {
 
 // Search will start the crawl and parse process for the given title and platform.
 func (m *Metacritic) Search(title string, platform Platform) ([]*Game, error) {
-	return m.startSearch(title, platform)
+	return m.SearchWithContext(context.Background(), title, platform)
+}
+
+// SearchWithContext is like Search but aborts the crawl when ctx is cancelled
+// or its deadline is exceeded, returning ctx.Err().
+func (m *Metacritic) SearchWithContext(ctx context.Context, title string, platform Platform) ([]*Game, error) {
+	games, _, err := m.startSearch(ctx, title, platform)
+	return games, err
+}
+
+// SearchWithReport is like SearchWithContext but additionally returns a report
+// of every crawled page, including the detail pages which failed.
+func (m *Metacritic) SearchWithReport(ctx context.Context, title string, platform Platform) ([]*Game, *SearchReport, error) {
+	return m.startSearch(ctx, title, platform)
+}
+
+// Games crawls the given game detail page urls, e.g. to retry the failed urls of a SearchReport.
+func (m *Metacritic) Games(ctx context.Context, urls []string) ([]*Game, *SearchReport, error) {
+	ctx, span := m.tracer().Start(ctx, "metacritic.Games", trace.WithAttributes(
+		attribute.Int("metacritic.urls", len(urls)),
+	))
+
+	report := &SearchReport{}
+
+	games := m.crawlGames(ctx, urls, report)
+	if err := ctx.Err(); err != nil {
+		endSpan(span, err)
+		return nil, report, err
+	}
+
+	span.SetAttributes(attribute.Int("metacritic.games", len(games)))
+	endSpan(span, nil)
+
+	return games, report, nil
 }
 
 // SearchBestMatch will call Search and returns then the best match.
@@ -134,10 +452,56 @@ func (m *Metacritic) Search(title string, platform Platform) ([]*Game, error) {
 // The best match is calculated with the "Dice's Coefficient" by the
 // using the external lib "https://github.com/hbakhtiyor/strsim".
 func (m *Metacritic) SearchBestMatch(title string, platform Platform) *Game {
-	games, err := m.Search(title, platform)
+	return m.SearchBestMatchWithContext(context.Background(), title, platform)
+}
+
+// SearchBestMatchWithContext is like SearchBestMatch but uses SearchWithContext.
+func (m *Metacritic) SearchBestMatchWithContext(ctx context.Context, title string, platform Platform) *Game {
+	game, _ := m.FindBestMatchWithContext(ctx, title, platform)
+	return game
+}
+
+// FindBestMatch is like SearchBestMatch but returns why no game was found.
+//
+// It returns ErrNotFound if the search had no result and the errors of the
+// detail pages if none of them could be crawled and parsed.
+func (m *Metacritic) FindBestMatch(title string, platform Platform) (*Game, error) {
+	return m.FindBestMatchWithContext(context.Background(), title, platform)
+}
+
+// noMatchError returns the joined errors of the failed pages of report or ErrNotFound if none failed.
+func noMatchError(report *SearchReport) error {
+	var errs []error
+	for _, page := range report.Failed() {
+		errs = append(errs, page.Error)
+	}
+
+	if len(errs) == 0 {
+		return ErrNotFound
+	}
+
+	return errors.Join(errs...)
+}
+
+// FindBestMatchWithContext is like FindBestMatch but uses SearchWithContext.
+func (m *Metacritic) FindBestMatchWithContext(ctx context.Context, title string, platform Platform) (game *Game, err error) {
+	ctx, span := m.tracer().Start(ctx, "metacritic.SearchBestMatch", trace.WithAttributes(
+		attribute.String("metacritic.title", title),
+		attribute.String("metacritic.platform", string(platform)),
+	))
+	defer func() { endSpan(span, err) }()
+
+	games, report, err := m.startSearch(ctx, title, platform)
 	if err != nil {
-		return nil
+		return nil, err
+	}
+
+	game = m.bestMatch(title, games)
+	if game == nil {
+		return nil, noMatchError(report)
 	}
 
-	return m.findBestMatch(title, games)
+	span.SetAttributes(attribute.String("url.full", game.Link))
+
+	return game, nil
 }
@@ -1,11 +1,17 @@
 package metacritic_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stahlstift/go-metacritic/pkg/metacritic"
 )
@@ -53,6 +59,14 @@ var mockClient = &MockClient{
 			res.Body = file
 		}
 
+		if req.URL.String() == "https://www.metacritic.com/search/game/Mario/results?plats[268409]=1&search_type=advanced&page=1" {
+			file, err := os.Open("./testdata/search_result_page2.html")
+			if err != nil {
+				return nil, err
+			}
+			res.Body = file
+		}
+
 		if req.URL.String() == "https://www.metacritic.com/game/switch/super-mario-party" {
 			file, err := os.Open("./testdata/mario_party.html")
 			if err != nil {
@@ -155,6 +169,79 @@ func TestMetacritic_SearchCrawlOneReturnsError(t *testing.T) {
 	}
 }
 
+// plainCrawler is a Crawler without the context variants.
+type plainCrawler struct {
+	crawler *metacritic.DefaultCrawler
+}
+
+func (c plainCrawler) Crawl(urls []string) []*metacritic.Result {
+	return c.crawler.Crawl(urls)
+}
+
+func (c plainCrawler) CrawlOne(url string) *metacritic.Result {
+	return c.crawler.CrawlOne(url)
+}
+
+func TestMetacritic_SearchWithoutContextCrawler(t *testing.T) {
+	t.Parallel()
+
+	mc := buildWithClient(mockClient)
+	mc.Crawler = plainCrawler{crawler: mc.Crawler.(*metacritic.DefaultCrawler)}
+
+	res, err := mc.SearchWithContext(context.Background(), "Mario", metacritic.Switch)
+	if err != nil {
+		t.Fatalf("SearchWithContext() returned an error '%s'", err)
+	}
+
+	if len(res) != 2 {
+		t.Fatalf("SearchWithContext() returned %d games instead of 2", len(res))
+	}
+}
+
+// plainParser is a Parser without the optional capabilities.
+type plainParser struct {
+	parser metacritic.DefaultParser
+}
+
+func (p plainParser) Game(body io.Reader) *metacritic.Game {
+	return p.parser.Game(body)
+}
+
+func (p plainParser) Search(body io.Reader) []string {
+	return p.parser.Search(body)
+}
+
+func TestMetacritic_SearchWithContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	mockClient := &MockClient{}
+	mockClient.DoFn = func(req *http.Request) (response *http.Response, err error) {
+		if req.URL.String() == "https://www.metacritic.com/search/game/Mario/results?plats[268409]=1&search_type=advanced" {
+			res := httptest.NewRecorder().Result()
+
+			file, err := os.Open("./testdata/search_result.html")
+			if err != nil {
+				return nil, err
+			}
+			res.Body = file
+			return res, nil
+		}
+
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	}
+
+	mc := buildWithClient(mockClient)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := mc.SearchWithContext(ctx, "Mario", metacritic.Switch)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("SearchWithContext() returned '%v' instead of '%s'", err, context.DeadlineExceeded)
+	}
+}
+
 func TestMetacritic_SearchBestMatch(t *testing.T) {
 	t.Parallel()
 
@@ -347,3 +434,176 @@ func TestMetacritic_SearchBestMatchWrongUserscore(t *testing.T) {
 		t.Fatalf("Wrong UserScore returned '%f' instead of '0'", res.UserScore)
 	}
 }
+
+func TestMetacritic_SearchStatusError(t *testing.T) {
+	t.Parallel()
+
+	mockClient := &MockClient{}
+	mockClient.DoFn = func(req *http.Request) (response *http.Response, err error) {
+		rec := httptest.NewRecorder()
+		rec.WriteHeader(http.StatusTooManyRequests)
+		return rec.Result(), nil
+	}
+
+	mc := buildWithClient(mockClient)
+
+	_, err := mc.Search("Mario", metacritic.Switch)
+	if !errors.Is(err, metacritic.ErrRateLimited) {
+		t.Fatalf("Search() returned '%v' instead of '%s'", err, metacritic.ErrRateLimited)
+	}
+
+	var statusErr *metacritic.HTTPStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("Search() returned '%v' without the status code", err)
+	}
+}
+
+func TestMetacritic_FindBestMatchNotFound(t *testing.T) {
+	t.Parallel()
+
+	mockClient := &MockClient{}
+	mockClient.DoFn = func(req *http.Request) (response *http.Response, err error) {
+		res := httptest.NewRecorder().Result()
+
+		file, err := os.Open("./testdata/search_result_no_result.html")
+		if err != nil {
+			return nil, err
+		}
+		res.Body = file
+
+		return res, nil
+	}
+
+	mc := buildWithClient(mockClient)
+
+	game, err := mc.FindBestMatch("Mario", metacritic.Switch)
+	if game != nil {
+		t.Fatalf("FindBestMatch() did returned a result")
+	}
+
+	if !errors.Is(err, metacritic.ErrNotFound) {
+		t.Fatalf("FindBestMatch() returned '%v' instead of '%s'", err, metacritic.ErrNotFound)
+	}
+}
+
+func TestMetacritic_FindBestMatch(t *testing.T) {
+	t.Parallel()
+
+	mc := buildWithClient(mockClient)
+
+	game, err := mc.FindBestMatch("Mario", metacritic.Switch)
+	if err != nil {
+		t.Fatalf("FindBestMatch() returned an error '%s'", err)
+	}
+
+	if game.Title != "Super Mario Party" {
+		t.Fatalf("FindBestMatch() returned '%s' instead of '%s'", game.Title, "Super Mario Party")
+	}
+}
+
+func TestMetacritic_SearchFirstPageOnly(t *testing.T) {
+	t.Parallel()
+
+	mc := buildWithClient(mockClient)
+
+	_, report, err := mc.SearchWithReport(context.Background(), "Mario", metacritic.Switch)
+	if err != nil {
+		t.Fatalf("SearchWithReport() returned an error '%s'", err)
+	}
+
+	if len(report.Pages) != 3 {
+		t.Fatalf("SearchWithReport() crawled %d pages instead of 3", len(report.Pages))
+	}
+}
+
+func TestMetacritic_SearchPagination(t *testing.T) {
+	t.Parallel()
+
+	mc := buildWithClient(mockClient)
+	mc.MaxPages = 5
+
+	games, report, err := mc.SearchWithReport(context.Background(), "Mario", metacritic.Switch)
+	if err != nil {
+		t.Fatalf("SearchWithReport() returned an error '%s'", err)
+	}
+
+	var urls []string
+	for _, page := range report.Pages {
+		urls = append(urls, page.URL)
+	}
+	want := []string{
+		"https://www.metacritic.com/search/game/Mario/results?plats[268409]=1&search_type=advanced",
+		"https://www.metacritic.com/search/game/Mario/results?plats[268409]=1&search_type=advanced&page=1",
+		"https://www.metacritic.com/game/switch/super-mario-party",
+		"https://www.metacritic.com/game/switch/super-mario-odyssey",
+		"https://www.metacritic.com/game/switch/mario-tennis-aces",
+	}
+	if !reflect.DeepEqual(urls, want) {
+		t.Fatalf("SearchWithReport() crawled %v instead of %v", urls, want)
+	}
+
+	// the mock has no detail page for mario tennis aces
+	if len(games) != 2 || len(report.Failed()) != 1 {
+		t.Fatalf("SearchWithReport() returned %d games and %d failed pages", len(games), len(report.Failed()))
+	}
+}
+
+func TestMetacritic_SearchMaxResults(t *testing.T) {
+	t.Parallel()
+
+	mc := buildWithClient(mockClient)
+	mc.MaxPages = 5
+	mc.MaxResults = 1
+
+	games, report, err := mc.SearchWithReport(context.Background(), "Mario", metacritic.Switch)
+	if err != nil {
+		t.Fatalf("SearchWithReport() returned an error '%s'", err)
+	}
+
+	if len(games) != 1 || len(report.Pages) != 2 {
+		t.Fatalf("SearchWithReport() returned %d games from %d pages instead of 1 from 2", len(games), len(report.Pages))
+	}
+}
+
+func TestMetacritic_SearchWithoutSearchPager(t *testing.T) {
+	t.Parallel()
+
+	mc := buildWithClient(mockClient)
+	mc.Parser = plainParser{}
+	mc.MaxPages = 5
+
+	games, report, err := mc.SearchWithReport(context.Background(), "Mario", metacritic.Switch)
+	if err != nil {
+		t.Fatalf("SearchWithReport() returned an error '%s'", err)
+	}
+
+	// without SearchPages only the first search result page is crawled
+	if len(games) != 2 || len(report.Pages) != 3 {
+		t.Fatalf("SearchWithReport() returned %d games from %d pages instead of 2 from 3", len(games), len(report.Pages))
+	}
+}
+
+func TestMetacritic_FindBestMatchDetailPagesFailed(t *testing.T) {
+	t.Parallel()
+
+	client := &MockClient{}
+	client.DoFn = func(req *http.Request) (*http.Response, error) {
+		if strings.HasPrefix(req.URL.Path, "/game/") {
+			rec := httptest.NewRecorder()
+			rec.WriteHeader(http.StatusTooManyRequests)
+			return rec.Result(), nil
+		}
+		return mockClient.Do(req)
+	}
+
+	mc := buildWithClient(client)
+
+	game, err := mc.FindBestMatch("Mario", metacritic.Switch)
+	if game != nil {
+		t.Fatalf("FindBestMatch() returned a result")
+	}
+
+	if !errors.Is(err, metacritic.ErrRateLimited) || errors.Is(err, metacritic.ErrNotFound) {
+		t.Fatalf("FindBestMatch() returned '%v' instead of '%s'", err, metacritic.ErrRateLimited)
+	}
+}
@@ -0,0 +1,124 @@
+// Package metrics exports Prometheus metrics of the metacritic crawler and parser.
+//
+// It is a module of its own, so only users of the metrics depend on Prometheus.
+//
+// A Collector is a metacritic.Observer and a prometheus.Collector:
+//
+//	collector := metrics.New()
+//	prometheus.MustRegister(collector)
+//
+//	crawler.Observer = collector
+//	parser.Observer = collector
+package metrics
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stahlstift/go-metacritic/pkg/metacritic"
+)
+
+// Collector records the events of a metacritic.Observer as Prometheus metrics.
+type Collector struct {
+	requests      *prometheus.CounterVec
+	latency       *prometheus.HistogramVec
+	retries       *prometheus.CounterVec
+	cacheHits     *prometheus.CounterVec
+	parseFailures *prometheus.CounterVec
+	inFlight      prometheus.Gauge
+}
+
+// New returns a Collector with all metrics in the namespace "metacritic".
+func New() *Collector {
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "metacritic",
+			Name:      "requests_total",
+			Help:      "Requests sent to metacritic by page type and status code, \"error\" if no response was received.",
+		}, []string{"page_type", "code"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "metacritic",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of requests sent to metacritic by page type.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"page_type"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "metacritic",
+			Name:      "retries_total",
+			Help:      "Retried requests by page type.",
+		}, []string{"page_type"}),
+		cacheHits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "metacritic",
+			Name:      "cache_hits_total",
+			Help:      "Responses served from a cache or revalidated by page type.",
+		}, []string{"page_type"}),
+		parseFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "metacritic",
+			Name:      "parse_failures_total",
+			Help:      "Pages which could not be parsed by page type and reason.",
+		}, []string{"page_type", "reason"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "metacritic",
+			Name:      "requests_in_flight",
+			Help:      "Requests sent to metacritic waiting for a response.",
+		}),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.latency.Describe(ch)
+	c.retries.Describe(ch)
+	c.cacheHits.Describe(ch)
+	c.parseFailures.Describe(ch)
+	c.inFlight.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.latency.Collect(ch)
+	c.retries.Collect(ch)
+	c.cacheHits.Collect(ch)
+	c.parseFailures.Collect(ch)
+	c.inFlight.Collect(ch)
+}
+
+// RequestStarted implements metacritic.Observer.
+func (c *Collector) RequestStarted(pageType metacritic.PageType) {
+	c.inFlight.Inc()
+}
+
+// RequestFinished implements metacritic.Observer.
+func (c *Collector) RequestFinished(pageType metacritic.PageType, statusCode int, latency time.Duration, err error) {
+	c.inFlight.Dec()
+
+	code := "error"
+	if statusCode > 0 {
+		code = strconv.Itoa(statusCode)
+	}
+	c.requests.WithLabelValues(string(pageType), code).Inc()
+	c.latency.WithLabelValues(string(pageType)).Observe(latency.Seconds())
+}
+
+// Retried implements metacritic.Observer.
+func (c *Collector) Retried(pageType metacritic.PageType, err error) {
+	c.retries.WithLabelValues(string(pageType)).Inc()
+}
+
+// CacheHit implements metacritic.Observer.
+func (c *Collector) CacheHit(pageType metacritic.PageType) {
+	c.cacheHits.WithLabelValues(string(pageType)).Inc()
+}
+
+// ParseFailed implements metacritic.Observer.
+func (c *Collector) ParseFailed(pageType metacritic.PageType, err error) {
+	reason := "other"
+	if errors.Is(err, metacritic.ErrLayoutChanged) {
+		reason = "layout_changed"
+	}
+	c.parseFailures.WithLabelValues(string(pageType), reason).Inc()
+}
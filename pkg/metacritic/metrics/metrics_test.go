@@ -0,0 +1,66 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stahlstift/go-metacritic/pkg/metacritic"
+	"github.com/stahlstift/go-metacritic/pkg/metacritic/metrics"
+)
+
+func TestCollector(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/missing") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("<html></html>"))
+	}))
+	defer server.Close()
+
+	collector := metrics.New()
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+
+	crawler := &metacritic.DefaultCrawler{
+		Client:     server.Client(),
+		Concurrent: 2,
+		UserAgent:  "unitTest",
+		Observer:   collector,
+	}
+	parser := &metacritic.DefaultParser{Observer: collector}
+
+	results := crawler.Crawl([]string{server.URL + "/game/pc/found", server.URL + "/game/pc/missing"})
+	if _, err := parser.ParseGame(results[0].Response.Body); err == nil {
+		t.Fatalf("ParseGame() returned no error for a page without ld+json")
+	}
+	results[0].Response.Body.Close()
+
+	expected := `
+# HELP metacritic_parse_failures_total Pages which could not be parsed by page type and reason.
+# TYPE metacritic_parse_failures_total counter
+metacritic_parse_failures_total{page_type="detail",reason="layout_changed"} 1
+# HELP metacritic_requests_in_flight Requests sent to metacritic waiting for a response.
+# TYPE metacritic_requests_in_flight gauge
+metacritic_requests_in_flight 0
+# HELP metacritic_requests_total Requests sent to metacritic by page type and status code, "error" if no response was received.
+# TYPE metacritic_requests_total counter
+metacritic_requests_total{code="200",page_type="detail"} 1
+metacritic_requests_total{code="404",page_type="detail"} 1
+`
+	err := testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"metacritic_parse_failures_total", "metacritic_requests_in_flight", "metacritic_requests_total")
+	if err != nil {
+		t.Fatalf("unexpected metrics: %s", err)
+	}
+
+	if n := testutil.CollectAndCount(collector, "metacritic_request_duration_seconds"); n != 1 {
+		t.Fatalf("request_duration_seconds has %d series instead of 1", n)
+	}
+}
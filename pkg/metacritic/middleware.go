@@ -0,0 +1,45 @@
+package metacritic
+
+import "net/http"
+
+// ClientFunc is a function implementing Client.
+type ClientFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls f(req).
+func (f ClientFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps a Client, e.g. to add headers, log or measure requests.
+type Middleware func(next Client) Client
+
+// Chain returns client wrapped by middlewares. The first middleware is the
+// outermost and sees the request first and the response last.
+func Chain(client Client, middlewares ...Middleware) Client {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		client = middlewares[i](client)
+	}
+
+	return client
+}
+
+// BeforeRequest returns a Middleware calling fn with every request before it is sent.
+func BeforeRequest(fn func(req *http.Request)) Middleware {
+	return func(next Client) Client {
+		return ClientFunc(func(req *http.Request) (*http.Response, error) {
+			fn(req)
+			return next.Do(req)
+		})
+	}
+}
+
+// AfterResponse returns a Middleware calling fn with every request and its response or error.
+func AfterResponse(fn func(req *http.Request, res *http.Response, err error)) Middleware {
+	return func(next Client) Client {
+		return ClientFunc(func(req *http.Request) (*http.Response, error) {
+			res, err := next.Do(req)
+			fn(req, res, err)
+			return res, err
+		})
+	}
+}
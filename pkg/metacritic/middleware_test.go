@@ -0,0 +1,80 @@
+package metacritic_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stahlstift/go-metacritic/pkg/metacritic"
+)
+
+func TestChainOrder(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+	trace := func(name string) metacritic.Middleware {
+		return func(next metacritic.Client) metacritic.Client {
+			return metacritic.ClientFunc(func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" before")
+				res, err := next.Do(req)
+				calls = append(calls, name+" after")
+				return res, err
+			})
+		}
+	}
+	client := metacritic.Chain(&MockClient{}, trace("a"), trace("b"))
+
+	req, _ := http.NewRequest(http.MethodGet, "http://www.example.org", nil)
+	_, _ = client.Do(req)
+
+	want := []string{"a before", "b before", "b after", "a after"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("Chain() called %v instead of %v", calls, want)
+	}
+}
+
+func TestCrawlerMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var sent []string
+	var seen []int
+
+	mock := &MockClient{}
+	mock.DoFn = func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		sent = append(sent, req.Header.Get("Traceparent"))
+		mu.Unlock()
+		return httptest.NewRecorder().Result(), nil
+	}
+
+	c := &metacritic.DefaultCrawler{
+		Client:     mock,
+		Concurrent: 2,
+		UserAgent:  userAgent,
+		Middleware: []metacritic.Middleware{
+			metacritic.BeforeRequest(func(req *http.Request) {
+				req.Header.Set("Traceparent", "trace")
+			}),
+			metacritic.AfterResponse(func(req *http.Request, res *http.Response, err error) {
+				mu.Lock()
+				seen = append(seen, res.StatusCode)
+				mu.Unlock()
+			}),
+		},
+	}
+
+	for _, result := range c.Crawl([]string{"http://www.example.org/1", "http://www.example.org/2"}) {
+		if result.Error != nil {
+			t.Fatalf("Crawl() returned an error '%s'", result.Error)
+		}
+	}
+	if !reflect.DeepEqual(sent, []string{"trace", "trace"}) {
+		t.Fatalf("BeforeRequest() header was sent as %v", sent)
+	}
+	if len(seen) != 2 {
+		t.Fatalf("AfterResponse() was called %d times instead of 2", len(seen))
+	}
+}
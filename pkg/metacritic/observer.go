@@ -0,0 +1,44 @@
+package metacritic
+
+import "time"
+
+// Observer is notified about the work of a DefaultCrawler, CachingCrawler and
+// DefaultParser, e.g. to export metrics. See the metrics package for a
+// Prometheus implementation.
+//
+// Implementations must be safe for concurrent use.
+type Observer interface {
+	// RequestStarted is called before a request is sent.
+	RequestStarted(pageType PageType)
+
+	// RequestFinished is called after a request returned. statusCode is 0 if
+	// no response was received.
+	RequestFinished(pageType PageType, statusCode int, latency time.Duration, err error)
+
+	// Retried is called before a failed request is sent again.
+	Retried(pageType PageType, err error)
+
+	// CacheHit is called if a response is served from a cache or revalidated.
+	CacheHit(pageType PageType)
+
+	// ParseFailed is called if a page cannot be parsed.
+	ParseFailed(pageType PageType, err error)
+}
+
+// nopObserver is the Observer used if none is set.
+type nopObserver struct{}
+
+func (nopObserver) RequestStarted(PageType)                             {}
+func (nopObserver) RequestFinished(PageType, int, time.Duration, error) {}
+func (nopObserver) Retried(PageType, error)                             {}
+func (nopObserver) CacheHit(PageType)                                   {}
+func (nopObserver) ParseFailed(PageType, error)                         {}
+
+// observerOrNop returns o or a no-op Observer if o is nil.
+func observerOrNop(o Observer) Observer {
+	if o == nil {
+		return nopObserver{}
+	}
+
+	return o
+}
@@ -0,0 +1,107 @@
+package metacritic_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stahlstift/go-metacritic/pkg/metacritic"
+)
+
+// recordingObserver counts the events of an Observer.
+type recordingObserver struct {
+	mu       sync.Mutex
+	started  int
+	statuses []int
+	retries  int
+	hits     int
+	failures int
+}
+
+func (o *recordingObserver) RequestStarted(metacritic.PageType) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.started++
+}
+
+func (o *recordingObserver) RequestFinished(_ metacritic.PageType, statusCode int, _ time.Duration, _ error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.statuses = append(o.statuses, statusCode)
+}
+
+func (o *recordingObserver) Retried(metacritic.PageType, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.retries++
+}
+
+func (o *recordingObserver) CacheHit(metacritic.PageType) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.hits++
+}
+
+func (o *recordingObserver) ParseFailed(metacritic.PageType, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.failures++
+}
+
+func TestObserverRequestsAndRetries(t *testing.T) {
+	t.Parallel()
+
+	client, _ := failingClient(2, http.StatusServiceUnavailable, nil)
+	observer := &recordingObserver{}
+	c := retryCrawler(client)
+	c.Observer = observer
+
+	result := c.CrawlOneWithContext(context.Background(), "http://www.metacritic.com/game/pc/x")
+	if result.Error != nil {
+		t.Fatalf("CrawlOneWithContext() returned an error '%s'", result.Error)
+	}
+
+	if observer.started != 3 || len(observer.statuses) != 3 || observer.retries != 2 {
+		t.Fatalf("Observer got %d started, %v finished and %d retries", observer.started, observer.statuses, observer.retries)
+	}
+	if observer.statuses[0] != http.StatusServiceUnavailable || observer.statuses[2] != http.StatusOK {
+		t.Fatalf("Observer got status codes %v", observer.statuses)
+	}
+}
+
+func TestObserverCacheHit(t *testing.T) {
+	t.Parallel()
+
+	client, _ := countingClient("body")
+	observer := &recordingObserver{}
+	c := metacritic.NewCachingCrawler(&metacritic.DefaultCrawler{
+		Client:     client,
+		Concurrent: 1,
+		UserAgent:  userAgent,
+	}, metacritic.NewMemoryCache(10))
+	c.Observer = observer
+
+	u := "http://www.metacritic.com/game/pc/x"
+	readBody(t, c.CrawlOneWithContext(context.Background(), u))
+	readBody(t, c.CrawlOneWithContext(context.Background(), u))
+
+	if observer.hits != 1 {
+		t.Fatalf("Observer got %d cache hits instead of 1", observer.hits)
+	}
+}
+
+func TestObserverParseFailed(t *testing.T) {
+	t.Parallel()
+
+	observer := &recordingObserver{}
+	p := metacritic.DefaultParser{Observer: observer}
+	if _, err := p.ParseGame(http.NoBody); err == nil {
+		t.Fatalf("ParseGame() returned no error")
+	}
+
+	if observer.failures != 1 {
+		t.Fatalf("Observer got %d parse failures instead of 1", observer.failures)
+	}
+}
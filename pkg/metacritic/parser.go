@@ -1,14 +1,29 @@
 package metacritic
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"strconv"
 	"strings"
+	"time"
 
 	"golang.org/x/net/html"
 )
 
+const (
+	datePublishedLayout = "January 2, 2006"
+	uploadDateLayout    = "2006-01-02 15:04:05"
+	reviewDateLayout    = "Jan 2, 2006"
+)
+
+// voidElements are the html elements without an end tag.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "param": true, "source": true, "track": true, "wbr": true,
+}
+
 type parsedGame struct {
 	Context         string `json:"@context"`
 	Type            string `json:"@type"`
@@ -23,9 +38,182 @@ type parsedGame struct {
 		RatingCount string `json:"ratingCount"`
 	} `json:"aggregateRating"`
 	ContentRating string `json:"contentRating"`
+
+	// Metadata is unmarshalled on its own, so a value in an unexpected
+	// shape cannot cost the scores.
+	Metadata parsedMetadata `json:"-"`
+}
+
+// parsedMetadata holds the optional parts of the application/ld+json script.
+type parsedMetadata struct {
+	DatePublished string     `json:"datePublished"`
+	Image         parsedText `json:"image"`
+	GamePlatform  parsedText `json:"gamePlatform"`
+	Trailer       *struct {
+		Type         string `json:"@type"`
+		Name         string `json:"name"`
+		Description  string `json:"description"`
+		ThumbnailURL string `json:"thumbnailUrl"`
+		UploadDate   string `json:"uploadDate"`
+	} `json:"trailer"`
+	Actor     parsedThings  `json:"actor"`
+	Publisher parsedThings  `json:"publisher"`
+	Genre     parsedStrings `json:"genre"`
+}
+
+// parsedThing is a schema.org Person or Organization.
+type parsedThing struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// parsedThings accepts a single parsedThing as well as a list of them,
+// as both are valid in schema.org.
+type parsedThings []parsedThing
+
+func (t *parsedThings) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '[' {
+		return json.Unmarshal(data, (*[]parsedThing)(t))
+	}
+
+	var thing parsedThing
+	if err := json.Unmarshal(data, &thing); err != nil {
+		return err
+	}
+	*t = parsedThings{thing}
+
+	return nil
+}
+
+// parsedStrings accepts a single string as well as a list of strings.
+type parsedStrings []string
+
+func (s *parsedStrings) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '[' {
+		return json.Unmarshal(data, (*[]string)(s))
+	}
+
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	*s = parsedStrings{str}
+
+	return nil
+}
+
+// parsedText accepts a string, a list whose first element is used, or an
+// object like schema.org ImageObject whose url (or name) is used.
+// Any other value leaves it empty.
+type parsedText string
+
+func (t *parsedText) UnmarshalJSON(data []byte) error {
+	var str string
+	if json.Unmarshal(data, &str) == nil {
+		*t = parsedText(str)
+		return nil
+	}
+
+	var list []parsedText
+	if json.Unmarshal(data, &list) == nil {
+		if len(list) > 0 {
+			*t = list[0]
+		}
+		return nil
+	}
+
+	var thing parsedThing
+	if json.Unmarshal(data, &thing) == nil {
+		if thing.URL != "" {
+			*t = parsedText(thing.URL)
+		} else {
+			*t = parsedText(thing.Name)
+		}
+	}
+
+	return nil
+}
+
+// DefaultParser parses the html of metacritic.
+type DefaultParser struct {
+	// Observer is notified about every game page which cannot be parsed.
+	Observer Observer
+}
+
+// attrValue returns the value of the attribute key of token.
+func attrValue(token html.Token, key string) string {
+	for _, attr := range token.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+
+	return ""
+}
+
+// hasClass reports whether class is one of the classes of token.
+func hasClass(token html.Token, class string) bool {
+	for _, c := range strings.Fields(attrValue(token, "class")) {
+		if c == class {
+			return true
+		}
+	}
+
+	return false
 }
 
-type DefaultParser struct{}
+// readText returns the whitespace normalized text of the element the tokenizer
+// just entered. The tokenizer is positioned at the end tag of the element afterwards.
+func readText(tokenizer *html.Tokenizer) string {
+	var words []string
+
+	depth := 0
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return strings.Join(words, " ")
+		case html.StartTagToken:
+			name, _ := tokenizer.TagName()
+			if !voidElements[string(name)] {
+				depth++
+			}
+		case html.EndTagToken:
+			if depth == 0 {
+				return strings.Join(words, " ")
+			}
+			depth--
+		case html.TextToken:
+			words = append(words, strings.Fields(string(tokenizer.Text()))...)
+		}
+	}
+}
+
+// parseReleaseYear returns the year of the release info of a search result like "Game, 2018" or 0.
+func parseReleaseYear(text string) int {
+	i := strings.LastIndex(text, ",")
+	year, err := strconv.Atoi(strings.TrimSpace(text[i+1:]))
+	if err != nil {
+		return 0
+	}
+
+	return year
+}
+
+// parseCount parses a count like "1,007" with thousands separators.
+func parseCount(text string) (int, error) {
+	return strconv.Atoi(strings.ReplaceAll(strings.TrimSpace(text), ",", ""))
+}
+
+// readPageNum returns the page number of a page_num element or 0.
+func readPageNum(tokenizer *html.Tokenizer) int {
+	page, err := strconv.Atoi(readText(tokenizer))
+	if err != nil {
+		return 0
+	}
+
+	return page
+}
 
 // Search tries to find urls on the search result page.
 func (p DefaultParser) Search(body io.Reader) []string {
@@ -35,49 +223,64 @@ func (p DefaultParser) Search(body io.Reader) []string {
 
 	found := false
 	for {
-		token := tokenizer.Next()
+		tt := tokenizer.Next()
 
-		if token == html.ErrorToken {
+		if tt == html.ErrorToken {
 			break
 		}
 
-		if token == html.StartTagToken {
-			if !found {
-				h3 := tokenizer.Token()
-
-				isH3 := h3.Data == "h3"
-				if isH3 {
-					for _, attr := range h3.Attr {
-						if attr.Key == "class" && strings.Contains(attr.Val, "product_title") {
-							found = true
-						}
-					}
-				}
+		if tt != html.StartTagToken {
+			continue
+		}
+
+		token := tokenizer.Token()
+		switch {
+		case token.Data == "h3" && hasClass(token, "product_title"):
+			found = true
+		case found && token.Data == "a":
+			if href := attrValue(token, "href"); strings.HasPrefix(href, "/game/") {
+				urls = append(urls, "https://www.metacritic.com"+href)
+				found = false
 			}
+		}
+	}
 
-			if found {
-				a := tokenizer.Token()
+	return urls
+}
 
-				isA := a.Data == "a"
-				if isA {
-					for _, attr := range a.Attr {
-						if attr.Key == "href" && strings.HasPrefix(attr.Val, "/game/") {
-							urls = append(urls, "https://www.metacritic.com"+attr.Val)
-							found = false
-						}
-					}
+// SearchPages returns the number of pages the search results are spread over.
+func (p DefaultParser) SearchPages(body io.Reader) int {
+	pages := 1
 
-				}
-			}
+	tokenizer := html.NewTokenizer(body)
+	for {
+		tt := tokenizer.Next()
 
+		if tt == html.ErrorToken {
+			break
+		}
+
+		if tt != html.StartTagToken {
+			continue
+		}
+
+		if hasClass(tokenizer.Token(), "page_num") {
+			if page := readPageNum(tokenizer); page > pages {
+				pages = page
+			}
 		}
 	}
 
-	return urls
+	return pages
 }
 
-func parseUserscore(tokenizer *html.Tokenizer) float32 {
+// parseUserscore returns the userscore, whether the game has a userscore at all
+// ("tbd" and unparsable values don't count) and the number of user ratings.
+//
+// The scores of the individual user reviews ("indiv") are not the userscore.
+func parseUserscore(tokenizer *html.Tokenizer) (float32, bool, int) {
 	var userscore float32
+	var valid bool
 
 	found := false
 	for {
@@ -99,7 +302,8 @@ func parseUserscore(tokenizer *html.Tokenizer) float32 {
 					if attr.Key == "class" &&
 						strings.Contains(attr.Val, "metascore_w") &&
 						strings.Contains(attr.Val, "user") &&
-						strings.Contains(attr.Val, "game") {
+						strings.Contains(attr.Val, "game") &&
+						!strings.Contains(attr.Val, "indiv") {
 						found = true
 
 						tokenizer.Next()
@@ -109,6 +313,7 @@ func parseUserscore(tokenizer *html.Tokenizer) float32 {
 						t, err := strconv.ParseFloat(value, 10)
 						if err == nil {
 							userscore = float32(t)
+							valid = true
 						}
 						break
 					}
@@ -117,31 +322,116 @@ func parseUserscore(tokenizer *html.Tokenizer) float32 {
 		}
 	}
 
-	return userscore
+	if !found {
+		return userscore, valid, 0
+	}
+
+	return userscore, valid, parseRatingCount(tokenizer)
 }
 
-func parseJson(tokenizer *html.Tokenizer) *parsedGame {
-	var parsedGame parsedGame
+// parseRatingCount reads the "based on 356 Ratings" summary following the userscore.
+//
+// The tokenizer has to be positioned inside the userscore div. The search stops
+// when the surrounding userscore_wrap div is closed.
+func parseRatingCount(tokenizer *html.Tokenizer) int {
+	depth := 1
+	for depth >= 0 {
+		token := tokenizer.Next()
 
-	stop := false
-	found := false
+		switch token {
+		case html.ErrorToken:
+			return 0
+		case html.StartTagToken:
+			if tokenizer.Token().Data == "div" {
+				depth++
+			}
+		case html.EndTagToken:
+			if tokenizer.Token().Data == "div" {
+				depth--
+			}
+		case html.TextToken:
+			fields := strings.Fields(string(tokenizer.Text()))
+			if len(fields) == 2 && strings.HasPrefix(fields[1], "Rating") {
+				count, err := parseCount(fields[0])
+				if err == nil {
+					return count
+				}
+			}
+		}
+	}
+
+	return 0
+}
+
+// parseDistributions parses the critic and user score distributions of the game detail page.
+func parseDistributions(tokenizer *html.Tokenizer) (ScoreDistribution, ScoreDistribution) {
+	var critic, user ScoreDistribution
+	var current *ScoreDistribution
+
+	count := 0
 	for {
-		if stop {
+		token := tokenizer.Next()
+
+		if token == html.ErrorToken {
 			break
 		}
 
+		if token != html.StartTagToken {
+			continue
+		}
+
+		tag := tokenizer.Token()
+
+		switch {
+		case hasClass(tag, "critic_reviews_module"):
+			current = &critic
+		case hasClass(tag, "user_reviews_module"):
+			current = &user
+		case current == nil:
+		case hasClass(tag, "score_count"):
+			count = 0
+		case hasClass(tag, "count"):
+			count, _ = parseCount(readText(tokenizer))
+		case hasClass(tag, "distribution"):
+			switch {
+			case hasClass(tag, string(Positive)):
+				current.Positive = count
+			case hasClass(tag, string(Mixed)):
+				current.Mixed = count
+			case hasClass(tag, string(Negative)):
+				current.Negative = count
+			}
+		}
+	}
+
+	return critic, user
+}
+
+// parseJson parses the application/ld+json script of the game detail page.
+//
+// It returns ErrLayoutChanged if the script is missing or its scores cannot be unmarshalled.
+func parseJson(tokenizer *html.Tokenizer) (*parsedGame, error) {
+	var parsedGame parsedGame
+
+	found := false
+	for {
 		token := tokenizer.Next()
 
 		if token == html.ErrorToken {
-			break
+			return nil, fmt.Errorf("%w: no application/ld+json script found", ErrLayoutChanged)
 		}
 
 		if found && token == html.TextToken {
-			stop = true
-			err := json.Unmarshal(tokenizer.Text(), &parsedGame)
+			text := tokenizer.Text()
+			err := json.Unmarshal(text, &parsedGame)
 			if err != nil {
-				return nil
+				return nil, fmt.Errorf("%w: %s", ErrLayoutChanged, err)
 			}
+
+			// whatever metadata can be unmarshalled is kept
+			_ = json.Unmarshal(text, &parsedGame.Metadata)
+
+			return &parsedGame, nil
 		}
 
 		if token == html.StartTagToken {
@@ -158,26 +448,296 @@ func parseJson(tokenizer *html.Tokenizer) *parsedGame {
 			}
 		}
 	}
+}
+
+// SearchHits tries to find the games listed on the search result page.
+//
+// It returns the games and the number of pages the search results are spread over.
+func (p DefaultParser) SearchHits(body io.Reader) ([]*SearchHit, int) {
+	var hits []*SearchHit
+	var hit *SearchHit
+	pages := 1
 
-	return &parsedGame
+	tokenizer := html.NewTokenizer(body)
+
+	title := false
+	release := false
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			var retVal []*SearchHit
+			for _, hit := range hits {
+				if hit.URL != "" {
+					retVal = append(retVal, hit)
+				}
+			}
+			return retVal, pages
+		case html.EndTagToken:
+			if name, _ := tokenizer.TagName(); string(name) == "p" {
+				release = false
+			}
+		case html.TextToken:
+			if release {
+				if year := parseReleaseYear(string(tokenizer.Text())); year > 0 {
+					hit.ReleaseYear = year
+				}
+			}
+		case html.StartTagToken:
+			token := tokenizer.Token()
+			switch {
+			case token.Data == "li" && hasClass(token, "result"):
+				hit = &SearchHit{}
+				hits = append(hits, hit)
+			case hasClass(token, "page_num"):
+				if page := readPageNum(tokenizer); page > pages {
+					pages = page
+				}
+			case hit == nil:
+				// not inside of a result
+			case hasClass(token, "metascore_w"):
+				score, err := strconv.Atoi(readText(tokenizer))
+				hit.MetaScore, hit.HasMetaScore = uint8(score), err == nil
+			case token.Data == "h3" && hasClass(token, "product_title"):
+				title = true
+			case title && token.Data == "a":
+				if href := attrValue(token, "href"); strings.HasPrefix(href, "/game/") {
+					hit.URL = "https://www.metacritic.com" + href
+					hit.Title = readText(tokenizer)
+					title = false
+				}
+			case hasClass(token, "platform"):
+				hit.Platform = readText(tokenizer)
+				release = true
+			case hasClass(token, "deck"):
+				hit.Description = readText(tokenizer)
+			}
+		}
+	}
 }
 
 // Game tries to find the scores on the game detail page.
+//
+// It returns nil if the page does not contain the game data, use ParseGame to get the reason.
 func (p DefaultParser) Game(body io.Reader) *Game {
+	game, _ := p.ParseGame(body)
+	return game
+}
+
+// ParseGame is like Game but returns ErrLayoutChanged if the page does not contain the game data.
+func (p DefaultParser) ParseGame(body io.Reader) (*Game, error) {
+	// the distributions are scanned separately, the userscore scan may have
+	// consumed the whole page when the game has no userscore
+	b, _ := io.ReadAll(body)
+	tokenizer := html.NewTokenizer(bytes.NewReader(b))
+
+	parsedGame, err := parseJson(tokenizer)
+	if err != nil {
+		observerOrNop(p.Observer).ParseFailed(DetailPage, err)
+		return nil, err
+	}
+
+	metascore, err := strconv.Atoi(parsedGame.AggregateRating.RatingValue)
+	hasMetascore := err == nil
+	criticCount, _ := strconv.Atoi(parsedGame.AggregateRating.RatingCount)
+	userscore, hasUserscore, userCount := parseUserscore(tokenizer)
+	criticDistribution, userDistribution := parseDistributions(html.NewTokenizer(bytes.NewReader(b)))
+
+	game := &Game{
+		Link:               parsedGame.URL,
+		Title:              parsedGame.Name,
+		MetaScore:          uint8(metascore),
+		HasMetaScore:       hasMetascore,
+		CriticReviewCount:  criticCount,
+		UserScore:          userscore,
+		HasUserScore:       hasUserscore,
+		UserRatingCount:    userCount,
+		CriticDistribution: criticDistribution,
+		UserDistribution:   userDistribution,
+		Description:        parsedGame.Description,
+		Image:              string(parsedGame.Metadata.Image),
+		ContentRating:      parsedGame.ContentRating,
+		Platform:           string(parsedGame.Metadata.GamePlatform),
+		Genres:             parsedGame.Metadata.Genre,
+	}
+
+	game.ReleaseDate, _ = time.Parse(datePublishedLayout, parsedGame.Metadata.DatePublished)
+
+	for _, p := range parsedGame.Metadata.Publisher {
+		game.Publishers = append(game.Publishers, Organization{Name: p.Name, URL: p.URL})
+	}
+
+	for _, a := range parsedGame.Metadata.Actor {
+		game.Credits = append(game.Credits, Person{Name: a.Name, URL: a.URL})
+	}
+
+	if t := parsedGame.Metadata.Trailer; t != nil {
+		game.Trailer = &Video{
+			Name:         t.Name,
+			Description:  t.Description,
+			ThumbnailURL: t.ThumbnailURL,
+		}
+		game.Trailer.UploadDate, _ = time.Parse(uploadDateLayout, t.UploadDate)
+	}
+
+	return game, nil
+}
+
+// parseReviews walks over the list items with the class reviewClass of a review page.
+//
+// start is called for every review and field for every start tag inside of the current review.
+// It returns the number of pages the reviews are spread over.
+func parseReviews(
+	body io.Reader,
+	reviewClass string,
+	start func(),
+	field func(tag html.Token, tokenizer *html.Tokenizer),
+) int {
+	pages := 1
+	inReview := false
+
 	tokenizer := html.NewTokenizer(body)
+	for {
+		token := tokenizer.Next()
 
-	parsedGame := parseJson(tokenizer)
-	if parsedGame == nil {
-		return nil
+		if token == html.ErrorToken {
+			break
+		}
+
+		if token == html.EndTagToken {
+			if name, _ := tokenizer.TagName(); string(name) == "ol" {
+				inReview = false
+			}
+			continue
+		}
+
+		if token != html.StartTagToken {
+			continue
+		}
+
+		tag := tokenizer.Token()
+
+		if hasClass(tag, "page_num") {
+			if page := readPageNum(tokenizer); page > pages {
+				pages = page
+			}
+			continue
+		}
+
+		if tag.Data == "li" && hasClass(tag, "review") {
+			inReview = hasClass(tag, reviewClass)
+			if inReview {
+				start()
+			}
+			continue
+		}
+
+		if inReview {
+			field(tag, tokenizer)
+		}
 	}
 
-	metascore, _ := strconv.Atoi(parsedGame.AggregateRating.RatingValue)
-	userscore := parseUserscore(tokenizer)
+	return pages
+}
 
-	return &Game{
-		Link:      parsedGame.URL,
-		Title:     parsedGame.Name,
-		MetaScore: uint8(metascore),
-		UserScore: userscore,
+// readBlurb returns the text of a review_body element.
+//
+// Long reviews contain a collapsed and an expanded blurb - only the expanded one is returned.
+func readBlurb(tokenizer *html.Tokenizer) string {
+	var words []string
+	var expanded string
+
+	depth := 0
+	for depth >= 0 {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			depth = -1
+		case html.StartTagToken:
+			tag := tokenizer.Token()
+			switch {
+			case hasClass(tag, "blurb_expanded"):
+				expanded = readText(tokenizer)
+			case hasClass(tag, "blurb_collapsed"), hasClass(tag, "blurb_etc"), tag.Data == "a":
+				readText(tokenizer)
+			case !voidElements[tag.Data]:
+				depth++
+			}
+		case html.EndTagToken:
+			depth--
+		case html.TextToken:
+			words = append(words, strings.Fields(string(tokenizer.Text()))...)
+		}
 	}
+
+	if expanded != "" {
+		return expanded
+	}
+
+	return strings.Join(words, " ")
+}
+
+// CriticReviews parses the critic reviews of a game detail or "/critic-reviews" page.
+//
+// It returns the reviews and the number of pages the critic reviews are spread over.
+func (p DefaultParser) CriticReviews(body io.Reader) ([]*CriticReview, int) {
+	var reviews []*CriticReview
+	var review *CriticReview
+
+	fullReview := false
+	pages := parseReviews(body, "critic_review", func() {
+		review = &CriticReview{}
+		reviews = append(reviews, review)
+		fullReview = false
+	}, func(tag html.Token, tokenizer *html.Tokenizer) {
+		switch {
+		case hasClass(tag, "source"):
+			review.Publication = readText(tokenizer)
+		case hasClass(tag, "author"):
+			review.Author = readText(tokenizer)
+		case hasClass(tag, "date"):
+			review.Date, _ = time.Parse(reviewDateLayout, readText(tokenizer))
+		case hasClass(tag, "metascore_w"):
+			score, _ := strconv.Atoi(readText(tokenizer))
+			review.Score = uint8(score)
+		case hasClass(tag, "review_body"):
+			review.Excerpt = readText(tokenizer)
+		case hasClass(tag, "full_review"):
+			fullReview = true
+		case fullReview && tag.Data == "a":
+			review.Link = attrValue(tag, "href")
+			fullReview = false
+		}
+	})
+
+	return reviews, pages
+}
+
+// UserReviews parses the user reviews of a game detail or "/user-reviews" page.
+//
+// It returns the reviews and the number of pages the user reviews are spread over.
+func (p DefaultParser) UserReviews(body io.Reader) ([]*UserReview, int) {
+	var reviews []*UserReview
+	var review *UserReview
+
+	pages := parseReviews(body, "user_review", func() {
+		review = &UserReview{}
+		reviews = append(reviews, review)
+	}, func(tag html.Token, tokenizer *html.Tokenizer) {
+		switch {
+		case hasClass(tag, "name"):
+			review.Author = readText(tokenizer)
+		case hasClass(tag, "date"):
+			review.Date, _ = time.Parse(reviewDateLayout, readText(tokenizer))
+		case hasClass(tag, "metascore_w"):
+			score, _ := strconv.Atoi(readText(tokenizer))
+			review.Score = uint8(score)
+		case hasClass(tag, "review_body"):
+			review.Text = readBlurb(tokenizer)
+		case hasClass(tag, "total_ups"):
+			review.HelpfulVotes, _ = parseCount(readText(tokenizer))
+		case hasClass(tag, "total_thumbs"):
+			review.TotalVotes, _ = parseCount(readText(tokenizer))
+		}
+	})
+
+	return reviews, pages
 }
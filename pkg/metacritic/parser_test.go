@@ -1,8 +1,13 @@
 package metacritic
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
 	"os"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestParseSearchPage(t *testing.T) {
@@ -20,6 +25,87 @@ func TestParseSearchPage(t *testing.T) {
 	}
 }
 
+func TestParseSearchPages(t *testing.T) {
+	t.Parallel()
+
+	file, err := os.Open("./testdata/search_result.html")
+	if err != nil {
+		t.Fatalf("error opening './testdata/search_result.html' ('%s')", err)
+	}
+
+	p := &DefaultParser{}
+	if pages := p.SearchPages(file); pages != 2 {
+		t.Fatalf("SearchPages() returned %d pages instead of 2", pages)
+	}
+}
+
+func TestParseSearchHits(t *testing.T) {
+	t.Parallel()
+
+	file, err := os.Open("./testdata/search_result.html")
+	if err != nil {
+		t.Fatalf("error opening './testdata/search_result.html' ('%s')", err)
+	}
+
+	p := &DefaultParser{}
+	hits, pages := p.SearchHits(file)
+	if len(hits) != 2 || pages != 2 {
+		t.Fatalf("SearchHits() returned %d hits on %d pages instead of 2 on 2", len(hits), pages)
+	}
+
+	want := SearchHit{
+		Title:        "Super Mario Party",
+		URL:          "https://www.metacritic.com/game/switch/super-mario-party",
+		Platform:     "Switch",
+		MetaScore:    76,
+		HasMetaScore: true,
+		Description: "The party comes to Nintendo Switch in this complete refresh of the Mario Party series. " +
+			"The Mario Party series is coming to the Nintendo Switch system with super-charged fun for everyone. The...",
+		ReleaseYear: 2018,
+	}
+	if *hits[0] != want {
+		t.Fatalf("SearchHits() returned %+v instead of %+v", *hits[0], want)
+	}
+	if hits[1].Title != "Super Mario Odyssey" || hits[1].MetaScore != 97 || hits[1].ReleaseYear != 2017 {
+		t.Fatalf("SearchHits() returned %+v for the second result", *hits[1])
+	}
+}
+
+func TestParseSearchHitsTBD(t *testing.T) {
+	t.Parallel()
+
+	file, err := os.Open("./testdata/search_result_page2.html")
+	if err != nil {
+		t.Fatalf("error opening './testdata/search_result_page2.html' ('%s')", err)
+	}
+
+	p := &DefaultParser{}
+	hits, pages := p.SearchHits(file)
+	if len(hits) != 2 || pages != 2 {
+		t.Fatalf("SearchHits() returned %d hits on %d pages instead of 2 on 2", len(hits), pages)
+	}
+
+	if hits[1].Title != "Mario Tennis Aces" || hits[1].HasMetaScore || hits[1].MetaScore != 0 {
+		t.Fatalf("SearchHits() returned %+v instead of a game without metascore", *hits[1])
+	}
+}
+
+func TestParseReleaseYear(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]int{
+		"Game, 2018 ": 2018,
+		"Game, TBA":   0,
+		"":            0,
+		"2017":        2017,
+	}
+	for text, want := range tests {
+		if got := parseReleaseYear(text); got != want {
+			t.Fatalf("parseReleaseYear('%s') returned %d instead of %d", text, got, want)
+		}
+	}
+}
+
 func TestParseGamePage(t *testing.T) {
 	t.Parallel()
 
@@ -42,3 +128,433 @@ func TestParseGamePage(t *testing.T) {
 		t.Fatalf("wrong userscore '%f' returned", game.UserScore)
 	}
 }
+
+func TestParseGamePageMetadata(t *testing.T) {
+	t.Parallel()
+
+	file, err := os.Open("./testdata/mario_party.html")
+	if err != nil {
+		t.Fatalf("error opening './testdata/mario_party.html' ('%s')", err)
+	}
+
+	p := &DefaultParser{}
+	game, err := p.ParseGame(file)
+	if err != nil {
+		t.Fatalf("error parsing game page ('%s')", err)
+	}
+
+	if !game.ReleaseDate.Equal(time.Date(2018, time.October, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("wrong release date '%s' returned", game.ReleaseDate)
+	}
+
+	if game.ContentRating != "ESRB E" {
+		t.Fatalf("wrong content rating '%s' returned", game.ContentRating)
+	}
+
+	if game.Platform != "Switch" {
+		t.Fatalf("wrong platform '%s' returned", game.Platform)
+	}
+
+	if len(game.Genres) != 2 || game.Genres[1] != "Party / Minigame" {
+		t.Fatalf("wrong genres '%v' returned", game.Genres)
+	}
+
+	if len(game.Publishers) != 1 || game.Publishers[0].URL != "https://www.metacritic.com/company/nintendo" {
+		t.Fatalf("wrong publishers '%v' returned", game.Publishers)
+	}
+
+	if len(game.Credits) != 3 || game.Credits[0].Name != "Charles Martinet" {
+		t.Fatalf("wrong credits '%v' returned", game.Credits)
+	}
+
+	if game.Trailer == nil || game.Trailer.UploadDate.IsZero() {
+		t.Fatalf("wrong trailer '%v' returned", game.Trailer)
+	}
+
+	if game.Description == "" || game.Image == "" {
+		t.Fatalf("description or image is missing")
+	}
+}
+
+func TestParsedThingsSingleObject(t *testing.T) {
+	t.Parallel()
+
+	var things parsedThings
+	err := json.Unmarshal([]byte(`{"@type": "Organization", "name": "Nintendo"}`), &things)
+	if err != nil {
+		t.Fatalf("error unmarshalling single object ('%s')", err)
+	}
+
+	if len(things) != 1 || things[0].Name != "Nintendo" {
+		t.Fatalf("wrong things '%v' returned", things)
+	}
+}
+
+func TestParsedText(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		json string
+		want parsedText
+	}{
+		{`"Switch"`, "Switch"},
+		{`["Switch", "PC"]`, "Switch"},
+		{`[]`, ""},
+		{`{"@type": "ImageObject", "url": "https://example.com/cover.jpg"}`, "https://example.com/cover.jpg"},
+		{`{"@type": "Thing", "name": "Switch"}`, "Switch"},
+		{`42`, ""},
+	}
+
+	for _, tt := range tests {
+		var text parsedText
+		if err := json.Unmarshal([]byte(tt.json), &text); err != nil {
+			t.Fatalf("error unmarshalling '%s' ('%s')", tt.json, err)
+		}
+
+		if text != tt.want {
+			t.Fatalf("wrong text '%s' returned for '%s'", text, tt.json)
+		}
+	}
+}
+
+func TestParseGameUnexpectedMetadata(t *testing.T) {
+	t.Parallel()
+
+	page := `<html><head><script type="application/ld+json">{
+		"name": "Super Mario Party",
+		"url": "https://www.metacritic.com/game/switch/super-mario-party",
+		"aggregateRating": {"ratingValue": "76", "ratingCount": "84"},
+		"image": {"@type": "ImageObject", "url": "https://example.com/cover.jpg"},
+		"gamePlatform": ["Switch"],
+		"genre": 42
+	}</script></head></html>`
+
+	p := &DefaultParser{}
+	game, err := p.ParseGame(strings.NewReader(page))
+	if err != nil {
+		t.Fatalf("error parsing game page ('%s')", err)
+	}
+
+	if !game.HasMetaScore || game.MetaScore != 76 || game.CriticReviewCount != 84 {
+		t.Fatalf("wrong scores returned for game '%+v'", game)
+	}
+
+	if game.Image != "https://example.com/cover.jpg" || game.Platform != "Switch" {
+		t.Fatalf("wrong image '%s' or platform '%s' returned", game.Image, game.Platform)
+	}
+}
+
+func TestParseGamePageCounts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		file              string
+		hasMetaScore      bool
+		hasUserScore      bool
+		criticReviewCount int
+		userRatingCount   int
+	}{
+		{"./testdata/mario_party.html", true, true, 84, 356},
+		{"./testdata/mario_odysee.html", true, true, 113, 4203},
+		{"./testdata/mario_odysee_no_meta.html", false, true, 0, 4203},
+		{"./testdata/mario_odysee_no_user.html", true, false, 113, 0},
+		{"./testdata/mario_odysee_wrong_user.html", true, false, 113, 4203},
+	}
+
+	for _, test := range tests {
+		file, err := os.Open(test.file)
+		if err != nil {
+			t.Fatalf("error opening '%s' ('%s')", test.file, err)
+		}
+
+		p := &DefaultParser{}
+		game, err := p.ParseGame(file)
+		file.Close()
+		if err != nil {
+			t.Fatalf("%s: error parsing game page ('%s')", test.file, err)
+		}
+
+		if game.HasMetaScore != test.hasMetaScore || game.HasUserScore != test.hasUserScore {
+			t.Fatalf("%s: wrong score presence (meta %t, user %t) returned", test.file, game.HasMetaScore, game.HasUserScore)
+		}
+
+		if game.CriticReviewCount != test.criticReviewCount {
+			t.Fatalf("%s: wrong critic review count '%d' returned", test.file, game.CriticReviewCount)
+		}
+
+		if game.UserRatingCount != test.userRatingCount {
+			t.Fatalf("%s: wrong user rating count '%d' returned", test.file, game.UserRatingCount)
+		}
+	}
+}
+
+func TestParseCriticReviews(t *testing.T) {
+	t.Parallel()
+
+	file, err := os.Open("./testdata/mario_party_critic_reviews.html")
+	if err != nil {
+		t.Fatalf("error opening './testdata/mario_party_critic_reviews.html' ('%s')", err)
+	}
+
+	p := &DefaultParser{}
+	reviews, pages := p.CriticReviews(file)
+	if pages != 2 {
+		t.Fatalf("wrong number of pages '%d' returned", pages)
+	}
+
+	if len(reviews) != 2 {
+		t.Fatalf("wrong number of reviews '%d' returned", len(reviews))
+	}
+
+	review := reviews[0]
+	if review.Publication != "PlayGround.ru" || review.Author != "Nikita Kazimirov" {
+		t.Fatalf("wrong publication '%s' or author '%s' returned", review.Publication, review.Author)
+	}
+
+	if review.Score != 90 {
+		t.Fatalf("wrong score '%d' returned", review.Score)
+	}
+
+	if !review.Date.Equal(time.Date(2018, time.November, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("wrong date '%s' returned", review.Date)
+	}
+
+	if review.Excerpt != "Super Mario Party is a perfect collection of fun and addictive mini-games." {
+		t.Fatalf("wrong excerpt '%s' returned", review.Excerpt)
+	}
+
+	if review.Link != "http://www.playground.ru/articles/zdorovo_velikolepno_obzor_super_mario_party-59588/" {
+		t.Fatalf("wrong link '%s' returned", review.Link)
+	}
+
+	if reviews[1].Author != "" || reviews[1].Link != "" || reviews[1].Score != 70 {
+		t.Fatalf("wrong review '%+v' returned", reviews[1])
+	}
+}
+
+func TestParseCriticReviewsGamePage(t *testing.T) {
+	t.Parallel()
+
+	file, err := os.Open("./testdata/mario_party.html")
+	if err != nil {
+		t.Fatalf("error opening './testdata/mario_party.html' ('%s')", err)
+	}
+
+	p := &DefaultParser{}
+	reviews, pages := p.CriticReviews(file)
+	if pages != 1 {
+		t.Fatalf("wrong number of pages '%d' returned", pages)
+	}
+
+	if len(reviews) != 7 {
+		t.Fatalf("wrong number of reviews '%d' returned", len(reviews))
+	}
+
+	last := reviews[6]
+	if last.Publication != "Game Rant" || last.Score != 50 {
+		t.Fatalf("wrong review '%+v' returned", last)
+	}
+}
+
+func TestParseUserReviews(t *testing.T) {
+	t.Parallel()
+
+	file, err := os.Open("./testdata/mario_party_user_reviews.html")
+	if err != nil {
+		t.Fatalf("error opening './testdata/mario_party_user_reviews.html' ('%s')", err)
+	}
+
+	p := &DefaultParser{}
+	reviews, pages := p.UserReviews(file)
+	if pages != 2 {
+		t.Fatalf("wrong number of pages '%d' returned", pages)
+	}
+
+	if len(reviews) != 2 {
+		t.Fatalf("wrong number of reviews '%d' returned", len(reviews))
+	}
+
+	review := reviews[0]
+	if review.Author != "yic191" || review.Score != 10 {
+		t.Fatalf("wrong author '%s' or score '%d' returned", review.Author, review.Score)
+	}
+
+	if !review.Date.Equal(time.Date(2018, time.October, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("wrong date '%s' returned", review.Date)
+	}
+
+	if review.HelpfulVotes != 29 || review.TotalVotes != 36 {
+		t.Fatalf("wrong votes '%d of %d' returned", review.HelpfulVotes, review.TotalVotes)
+	}
+
+	expanded := "Super Mario Party is one of the most fun Switch games out right now. There are tons of " +
+		"different mini games and modes that make it interesting to try to unlock all of the characters, " +
+		"difficulties, and challenge modes. Honestly the only bad thing I would have to say about this game " +
+		"is that there are only 4 boards so far."
+	if reviews[1].Text != expanded {
+		t.Fatalf("wrong text '%s' returned", reviews[1].Text)
+	}
+}
+
+func TestParseUserReviewsGamePage(t *testing.T) {
+	t.Parallel()
+
+	file, err := os.Open("./testdata/mario_party.html")
+	if err != nil {
+		t.Fatalf("error opening './testdata/mario_party.html' ('%s')", err)
+	}
+
+	p := &DefaultParser{}
+	reviews, _ := p.UserReviews(file)
+	if len(reviews) != 7 {
+		t.Fatalf("wrong number of reviews '%d' returned", len(reviews))
+	}
+
+	if reviews[0].Text != "Nice game, so many modes, and so much fun minigames. I want to see more stages to play in the party mode." {
+		t.Fatalf("wrong text '%s' returned", reviews[0].Text)
+	}
+
+	if strings.Contains(reviews[1].Text, "Expand") || !strings.HasSuffix(reviews[1].Text, "it isn't as enjoyable.") {
+		t.Fatalf("wrong text '%s' returned", reviews[1].Text)
+	}
+}
+
+func TestParseGamePageDistribution(t *testing.T) {
+	t.Parallel()
+
+	file, err := os.Open("./testdata/mario_party.html")
+	if err != nil {
+		t.Fatalf("error opening './testdata/mario_party.html' ('%s')", err)
+	}
+
+	p := &DefaultParser{}
+	game, err := p.ParseGame(file)
+	if err != nil {
+		t.Fatalf("error parsing game page ('%s')", err)
+	}
+
+	critic := ScoreDistribution{Positive: 58, Mixed: 26, Negative: 0}
+	if game.CriticDistribution != critic {
+		t.Fatalf("wrong critic distribution '%+v' returned", game.CriticDistribution)
+	}
+
+	user := ScoreDistribution{Positive: 67, Mixed: 24, Negative: 25}
+	if game.UserDistribution != user {
+		t.Fatalf("wrong user distribution '%+v' returned", game.UserDistribution)
+	}
+}
+
+func TestParseGamePageDistributionThousands(t *testing.T) {
+	t.Parallel()
+
+	file, err := os.Open("./testdata/mario_odysee.html")
+	if err != nil {
+		t.Fatalf("error opening './testdata/mario_odysee.html' ('%s')", err)
+	}
+
+	p := &DefaultParser{}
+	game, err := p.ParseGame(file)
+	if err != nil {
+		t.Fatalf("error parsing game page ('%s')", err)
+	}
+
+	user := ScoreDistribution{Positive: 1007, Mixed: 60, Negative: 35}
+	if game.UserDistribution != user {
+		t.Fatalf("wrong user distribution '%+v' returned", game.UserDistribution)
+	}
+}
+
+func TestParseGamePageDistributionWithoutUserscore(t *testing.T) {
+	t.Parallel()
+
+	file, err := os.Open("./testdata/mario_odysee_no_userscore.html")
+	if err != nil {
+		t.Fatalf("error opening './testdata/mario_odysee_no_userscore.html' ('%s')", err)
+	}
+
+	p := &DefaultParser{}
+	game, err := p.ParseGame(file)
+	if err != nil {
+		t.Fatalf("error parsing game page ('%s')", err)
+	}
+
+	if game.HasUserScore {
+		t.Fatalf("userscore '%f' returned for a page without userscore", game.UserScore)
+	}
+
+	critic := ScoreDistribution{Positive: 113, Mixed: 0, Negative: 0}
+	if game.CriticDistribution != critic {
+		t.Fatalf("wrong critic distribution '%+v' returned", game.CriticDistribution)
+	}
+
+	user := ScoreDistribution{Positive: 1007, Mixed: 60, Negative: 35}
+	if game.UserDistribution != user {
+		t.Fatalf("wrong user distribution '%+v' returned", game.UserDistribution)
+	}
+}
+
+func TestParseCount(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]int{
+		"1,007":     1007,
+		" 12 ":      12,
+		"1,234,567": 1234567,
+	}
+	for text, want := range tests {
+		if got, err := parseCount(text); err != nil || got != want {
+			t.Fatalf("parseCount('%s') returned %d, %v instead of %d", text, got, err, want)
+		}
+	}
+
+	if _, err := parseCount("tbd"); err == nil {
+		t.Fatalf("parseCount('tbd') returned no error")
+	}
+}
+
+func TestParseGamePageLayoutChanged(t *testing.T) {
+	t.Parallel()
+
+	file, err := os.Open("./testdata/search_result.html")
+	if err != nil {
+		t.Fatalf("error opening './testdata/search_result.html' ('%s')", err)
+	}
+
+	p := &DefaultParser{}
+	game, err := p.ParseGame(file)
+	if game != nil {
+		t.Fatalf("game '%+v' returned for a search page", game)
+	}
+
+	if !errors.Is(err, ErrLayoutChanged) {
+		t.Fatalf("wrong error '%v' returned", err)
+	}
+}
+
+// gameOnlyParser is a Parser without ParseGame.
+type gameOnlyParser struct {
+	parser DefaultParser
+}
+
+func (p gameOnlyParser) Game(body io.Reader) *Game {
+	return p.parser.Game(body)
+}
+
+func (p gameOnlyParser) Search(body io.Reader) []string {
+	return p.parser.Search(body)
+}
+
+func TestParseGameBodyWithoutGameParser(t *testing.T) {
+	t.Parallel()
+
+	file, err := os.Open("./testdata/search_result.html")
+	if err != nil {
+		t.Fatalf("error opening './testdata/search_result.html' ('%s')", err)
+	}
+	defer file.Close()
+
+	game, err := parseGameBody(gameOnlyParser{}, file)
+	if game != nil || !errors.Is(err, ErrLayoutChanged) {
+		t.Fatalf("parseGameBody() returned '%v' and '%v' for a search page", game, err)
+	}
+}
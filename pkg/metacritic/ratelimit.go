@@ -0,0 +1,74 @@
+package metacritic
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// HostLimit is the token bucket configuration for a single host.
+type HostLimit struct {
+	Rate  float64 // requests per second, 0 means unlimited
+	Burst int     // maximum number of requests sent at once
+}
+
+// RateLimiter is a token bucket rate limiter with one bucket per host.
+//
+// A RateLimiter is safe for concurrent use. All Crawl and CrawlOne calls of a
+// DefaultCrawler share its RateLimiter, so the limits apply to the crawler as a whole.
+type RateLimiter struct {
+	// Default is used for every host without an entry in PerHost.
+	Default HostLimit
+
+	// PerHost overrides the limit for specific hosts, e.g. "www.metacritic.com".
+	PerHost map[string]HostLimit
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// NewRateLimiter returns a RateLimiter allowing rps requests per second with
+// the given burst for every host.
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		Default: HostLimit{Rate: rps, Burst: burst},
+	}
+}
+
+// limiter returns the bucket of host, creating it on first use.
+func (l *RateLimiter) limiter(host string) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limiter, ok := l.limiters[host]; ok {
+		return limiter
+	}
+
+	limit, ok := l.PerHost[host]
+	if !ok {
+		limit = l.Default
+	}
+
+	r := rate.Inf
+	if limit.Rate > 0 {
+		r = rate.Limit(limit.Rate)
+	}
+
+	burst := limit.Burst
+	if burst < 1 {
+		burst = 1
+	}
+
+	if l.limiters == nil {
+		l.limiters = make(map[string]*rate.Limiter)
+	}
+	l.limiters[host] = rate.NewLimiter(r, burst)
+
+	return l.limiters[host]
+}
+
+// Wait blocks until a request to host is allowed or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context, host string) error {
+	return l.limiter(host).Wait(ctx)
+}
@@ -0,0 +1,69 @@
+package metacritic_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stahlstift/go-metacritic/pkg/metacritic"
+)
+
+func TestRateLimiterSharedAcrossCrawls(t *testing.T) {
+	t.Parallel()
+
+	c := &metacritic.DefaultCrawler{
+		Client:      &MockClient{},
+		Concurrent:  5,
+		UserAgent:   userAgent,
+		RateLimiter: metacritic.NewRateLimiter(50, 1),
+	}
+
+	urls := []string{
+		"http://www.example.org/1", "http://www.example.org/2", "http://www.example.org/3",
+		"http://www.example.org/4", "http://www.example.org/5",
+	}
+
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Crawl(urls)
+		}()
+	}
+	wg.Wait()
+
+	// 10 requests with a burst of 1 need at least 9 intervals of 20ms.
+	if elapsed := time.Since(start); elapsed < 180*time.Millisecond {
+		t.Fatalf("10 requests took %s - the rate limit was not applied", elapsed)
+	}
+}
+
+func TestRateLimiterPerHost(t *testing.T) {
+	t.Parallel()
+
+	l := metacritic.NewRateLimiter(1, 1)
+	l.PerHost = map[string]metacritic.HostLimit{
+		"fast.example.org": {Rate: 0},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	for i := 0; i < 10; i++ {
+		if err := l.Wait(ctx, "fast.example.org"); err != nil {
+			t.Fatalf("Wait() returned an error '%s' for an unlimited host", err)
+		}
+	}
+
+	if err := l.Wait(ctx, "www.example.org"); err != nil {
+		t.Fatalf("Wait() returned an error '%s' for the first request", err)
+	}
+
+	if err := l.Wait(ctx, "www.example.org"); err == nil {
+		t.Fatalf("Wait() did not return an error although the deadline is before the next token")
+	}
+}
@@ -0,0 +1,67 @@
+package metacritic
+
+import (
+	"errors"
+	"time"
+)
+
+// PageReport is the outcome of a single page crawled by a search.
+type PageReport struct {
+	URL        string
+	StatusCode int // 0 if no response was received
+	Latency    time.Duration
+	Error      error // transport, status or parse error
+}
+
+// SearchReport lists every page crawled by a search - the search result page
+// first, followed by the detail pages.
+type SearchReport struct {
+	Pages []PageReport
+}
+
+func newPageReport(url string, result *Result, err error) PageReport {
+	page := PageReport{
+		URL:   url,
+		Error: err,
+	}
+
+	if result != nil {
+		page.Latency = result.Latency
+		if result.Response != nil {
+			page.StatusCode = result.Response.StatusCode
+		}
+	}
+
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		page.StatusCode = statusErr.StatusCode
+	}
+
+	return page
+}
+
+func (r *SearchReport) add(page PageReport) {
+	r.Pages = append(r.Pages, page)
+}
+
+// Failed returns the reports of all pages which could not be crawled or parsed.
+func (r *SearchReport) Failed() []PageReport {
+	var failed []PageReport
+	for _, page := range r.Pages {
+		if page.Error != nil {
+			failed = append(failed, page)
+		}
+	}
+
+	return failed
+}
+
+// FailedURLs returns the urls of all pages which could not be crawled or parsed.
+func (r *SearchReport) FailedURLs() []string {
+	var urls []string
+	for _, page := range r.Failed() {
+		urls = append(urls, page.URL)
+	}
+
+	return urls
+}
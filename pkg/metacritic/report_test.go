@@ -0,0 +1,89 @@
+package metacritic_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stahlstift/go-metacritic/pkg/metacritic"
+)
+
+func TestMetacritic_SearchWithReport(t *testing.T) {
+	t.Parallel()
+
+	mockClient := &MockClient{}
+	mockClient.DoFn = func(req *http.Request) (response *http.Response, err error) {
+		res := httptest.NewRecorder().Result()
+
+		if req.URL.String() == "https://www.metacritic.com/search/game/Mario/results?plats[268409]=1&search_type=advanced" {
+			file, err := os.Open("./testdata/search_result.html")
+			if err != nil {
+				return nil, err
+			}
+			res.Body = file
+		}
+
+		if req.URL.String() == "https://www.metacritic.com/game/switch/super-mario-party" {
+			file, err := os.Open("./testdata/mario_party.html")
+			if err != nil {
+				return nil, err
+			}
+			res.Body = file
+		}
+
+		if req.URL.String() == "https://www.metacritic.com/game/switch/super-mario-odyssey" {
+			return nil, fmt.Errorf("unittest")
+		}
+
+		return res, nil
+	}
+
+	mc := buildWithClient(mockClient)
+
+	games, report, err := mc.SearchWithReport(context.Background(), "Mario", metacritic.Switch)
+	if err != nil {
+		t.Fatalf("SearchWithReport() returned an error '%s'", err)
+	}
+
+	if len(games) != 1 {
+		t.Fatalf("SearchWithReport() returned %d games instead of 1", len(games))
+	}
+
+	if len(report.Pages) != 3 {
+		t.Fatalf("SearchWithReport() reported %d pages instead of 3", len(report.Pages))
+	}
+
+	if report.Pages[0].StatusCode != http.StatusOK || report.Pages[0].Error != nil {
+		t.Fatalf("SearchWithReport() reported the search page as '%+v'", report.Pages[0])
+	}
+
+	failed := report.FailedURLs()
+	if len(failed) != 1 || failed[0] != "https://www.metacritic.com/game/switch/super-mario-odyssey" {
+		t.Fatalf("SearchWithReport() reported '%v' as failed", failed)
+	}
+}
+
+func TestMetacritic_Games(t *testing.T) {
+	t.Parallel()
+
+	mc := buildWithClient(mockClient)
+
+	games, report, err := mc.Games(context.Background(), []string{
+		"https://www.metacritic.com/game/switch/super-mario-odyssey",
+		"https://www.metacritic.com/game/switch/unknown",
+	})
+	if err != nil {
+		t.Fatalf("Games() returned an error '%s'", err)
+	}
+
+	if len(games) != 1 || games[0].Title != "Super Mario Odyssey" {
+		t.Fatalf("Games() returned wrong games")
+	}
+
+	if len(report.Failed()) != 1 {
+		t.Fatalf("Games() reported %d failed pages instead of 1", len(report.Failed()))
+	}
+}
@@ -0,0 +1,132 @@
+package metacritic
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy configures how the DefaultCrawler retries failed requests.
+//
+// The delay before retry n is BaseDelay * 2^(n-1) capped at MaxDelay, reduced by
+// a random fraction of up to Jitter. A Retry-After header of the response is
+// honored - if it asks for a longer delay than MaxDelay the request is not retried.
+type RetryPolicy struct {
+	MaxAttempts int // including the first attempt
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Jitter      float64 // between 0 and 1
+
+	// RetryableStatusCodes are the status codes which are retried.
+	// If nil, 429, 500, 502, 503 and 504 are retried.
+	RetryableStatusCodes []int
+
+	// RetryableError reports whether a transport error is retried.
+	// If nil, IsTemporaryError is used.
+	RetryableError func(err error) bool
+}
+
+// DefaultRetryPolicy returns the RetryPolicy used by New.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+		Jitter:      0.5,
+	}
+}
+
+var defaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// IsTemporaryError reports whether err is a timeout, a connection reset or refused,
+// or a connection closed before the response was complete.
+func IsTemporaryError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// retryable reports whether a request failing with err should be retried.
+func (p *RetryPolicy) retryable(err error) bool {
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		codes := p.RetryableStatusCodes
+		if codes == nil {
+			codes = defaultRetryableStatusCodes
+		}
+
+		for _, code := range codes {
+			if code == statusErr.StatusCode {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if p.RetryableError != nil {
+		return p.RetryableError(err)
+	}
+
+	return IsTemporaryError(err)
+}
+
+// delay returns how long to wait before the given retry (starting with 1) of a
+// request which failed with err. It returns false if the request should not be retried.
+func (p *RetryPolicy) delay(retry int, err error) (time.Duration, bool) {
+	if retry >= p.MaxAttempts || !p.retryable(err) {
+		return 0, false
+	}
+
+	delay := p.BaseDelay
+	for i := 1; i < retry && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		delay -= time.Duration(p.Jitter * rand.Float64() * float64(delay))
+	}
+
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) && statusErr.RetryAfter > delay {
+		if statusErr.RetryAfter > p.MaxDelay {
+			return 0, false
+		}
+		delay = statusErr.RetryAfter
+	}
+
+	return delay, true
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
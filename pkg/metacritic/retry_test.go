@@ -0,0 +1,149 @@
+package metacritic_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stahlstift/go-metacritic/pkg/metacritic"
+)
+
+// failingClient fails the first failures requests with status or err.
+func failingClient(failures int32, status int, err error) (*MockClient, *int32) {
+	var requests int32
+
+	mock := &MockClient{}
+	mock.DoFn = func(req *http.Request) (response *http.Response, e error) {
+		if atomic.AddInt32(&requests, 1) > failures {
+			return httptest.NewRecorder().Result(), nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		rec := httptest.NewRecorder()
+		rec.WriteHeader(status)
+		return rec.Result(), nil
+	}
+
+	return mock, &requests
+}
+
+func retryCrawler(c metacritic.Client) *metacritic.DefaultCrawler {
+	return &metacritic.DefaultCrawler{
+		Client:     c,
+		Concurrent: 1,
+		UserAgent:  userAgent,
+		Retry: &metacritic.RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Millisecond,
+			MaxDelay:    10 * time.Millisecond,
+			Jitter:      0.5,
+		},
+	}
+}
+
+func TestCrawlRetry(t *testing.T) {
+	t.Parallel()
+
+	mock, requests := failingClient(2, http.StatusServiceUnavailable, nil)
+	res := retryCrawler(mock).CrawlOne("http://www.example.org")
+	if res.Error != nil {
+		t.Fatalf("CrawlOne() returned an error '%s'", res.Error)
+	}
+
+	if res.Attempts != 3 || atomic.LoadInt32(requests) != 3 {
+		t.Fatalf("CrawlOne() needed %d attempts instead of 3", res.Attempts)
+	}
+}
+
+func TestCrawlRetryTransportError(t *testing.T) {
+	t.Parallel()
+
+	mock, _ := failingClient(1, 0, syscall.ECONNRESET)
+	res := retryCrawler(mock).CrawlOne("http://www.example.org")
+	if res.Error != nil || res.Attempts != 2 {
+		t.Fatalf("CrawlOne() returned '%v' after %d attempts", res.Error, res.Attempts)
+	}
+}
+
+func TestCrawlRetryGivesUp(t *testing.T) {
+	t.Parallel()
+
+	mock, requests := failingClient(5, http.StatusBadGateway, nil)
+	res := retryCrawler(mock).CrawlOne("http://www.example.org")
+
+	var statusErr *metacritic.HTTPStatusError
+	if !errors.As(res.Error, &statusErr) || statusErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("CrawlOne() returned '%v' instead of status 502", res.Error)
+	}
+
+	if res.Attempts != 3 || atomic.LoadInt32(requests) != 3 {
+		t.Fatalf("CrawlOne() needed %d attempts instead of 3", res.Attempts)
+	}
+}
+
+func TestCrawlRetryNotRetryable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		status int
+		err    error
+	}{
+		{http.StatusNotFound, nil},
+		{0, errors.New("unittest")},
+	}
+
+	for _, test := range tests {
+		mock, _ := failingClient(1, test.status, test.err)
+		res := retryCrawler(mock).CrawlOne("http://www.example.org")
+		if res.Error == nil || res.Attempts != 1 {
+			t.Fatalf("CrawlOne() retried a not retryable error '%v'", res.Error)
+		}
+	}
+}
+
+func TestCrawlRetryAfterExceedsMaxDelay(t *testing.T) {
+	t.Parallel()
+
+	mock := &MockClient{}
+	mock.DoFn = func(req *http.Request) (response *http.Response, err error) {
+		rec := httptest.NewRecorder()
+		rec.Header().Set("Retry-After", "3600")
+		rec.WriteHeader(http.StatusTooManyRequests)
+		return rec.Result(), nil
+	}
+
+	res := retryCrawler(mock).CrawlOne("http://www.example.org")
+	if !errors.Is(res.Error, metacritic.ErrRateLimited) || res.Attempts != 1 {
+		t.Fatalf("CrawlOne() returned '%v' after %d attempts", res.Error, res.Attempts)
+	}
+}
+
+func TestCrawlRetryContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	mock, _ := failingClient(5, http.StatusServiceUnavailable, nil)
+	c := retryCrawler(mock)
+	c.Retry.BaseDelay = time.Hour
+	c.Retry.MaxDelay = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	res := c.CrawlOneWithContext(ctx, "http://www.example.org")
+	if res.Error == nil || res.Attempts != 1 {
+		t.Fatalf("CrawlOneWithContext() returned '%v' after %d attempts", res.Error, res.Attempts)
+	}
+
+	if time.Since(start) > time.Second {
+		t.Fatalf("CrawlOneWithContext() did not stop waiting for the retry")
+	}
+}
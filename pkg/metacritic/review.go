@@ -0,0 +1,168 @@
+package metacritic
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CriticReview is a review of a publication listed on metacritic.
+type CriticReview struct {
+	Publication string
+	Author      string
+	Score       uint8
+	Date        time.Time
+	Excerpt     string
+	Link        string // link to the full review on the publication's site
+}
+
+// UserReview is a review written by a metacritic user.
+type UserReview struct {
+	Author       string
+	Score        uint8 // 0 - 10
+	Date         time.Time
+	Text         string
+	HelpfulVotes int // users who found the review helpful
+	TotalVotes   int // users who voted on the helpfulness
+}
+
+// CriticReviewParser is a Parser which extracts the critic reviews of a game.
+//
+// It returns the reviews and the number of pages they are spread over.
+// CriticReviews requires the Parser of Metacritic to implement it.
+type CriticReviewParser interface {
+	CriticReviews(body io.Reader) ([]*CriticReview, int)
+}
+
+// UserReviewParser is a Parser which extracts the user reviews of a game.
+//
+// It returns the reviews and the number of pages they are spread over.
+// UserReviews and UserReviewsPage require the Parser of Metacritic to implement it.
+type UserReviewParser interface {
+	UserReviews(body io.Reader) ([]*UserReview, int)
+}
+
+// subpageURL returns the url of page (starting with 0) of the subpage of the game detail page gameURL.
+func subpageURL(gameURL, subpage string, page int) string {
+	u := strings.TrimSuffix(gameURL, "/") + "/" + subpage
+	if page > 0 {
+		u += "?page=" + strconv.Itoa(page)
+	}
+
+	return u
+}
+
+// crawlPage crawls the page u and calls parse with its body.
+func (m *Metacritic) crawlPage(ctx context.Context, u string, parse func(body io.Reader)) error {
+	result := crawlOne(ctx, m.Crawler, u)
+	if err := ctx.Err(); err != nil {
+		if result != nil && result.Error == nil {
+			result.Response.Body.Close()
+		}
+		return err
+	}
+
+	if err := resultError(result, u); err != nil {
+		return fmt.Errorf("cannot crawl page %s: %w", u, err)
+	}
+
+	defer result.Response.Body.Close()
+	parse(result.Response.Body)
+
+	return nil
+}
+
+// crawlPages crawls all pages of the subpage of the game detail page gameURL.
+//
+// parse is called with the body of every page and has to return the number of pages.
+func (m *Metacritic) crawlPages(ctx context.Context, gameURL, subpage string, parse func(body io.Reader) int) error {
+	for page, pages := 0, 1; page < pages; page++ {
+		err := m.crawlPage(ctx, subpageURL(gameURL, subpage, page), func(body io.Reader) {
+			pages = parse(body)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CriticReviews returns all critic reviews for the game detail page gameURL.
+//
+// It follows the "/critic-reviews" subpage and all of its pages. It returns
+// ErrUnsupported if the Parser is no CriticReviewParser.
+func (m *Metacritic) CriticReviews(gameURL string) ([]*CriticReview, error) {
+	return m.CriticReviewsWithContext(context.Background(), gameURL)
+}
+
+// CriticReviewsWithContext is like CriticReviews but aborts the crawl when ctx is cancelled.
+func (m *Metacritic) CriticReviewsWithContext(ctx context.Context, gameURL string) ([]*CriticReview, error) {
+	parser, ok := m.Parser.(CriticReviewParser)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+
+	var reviews []*CriticReview
+
+	err := m.crawlPages(ctx, gameURL, "critic-reviews", func(body io.Reader) int {
+		r, pages := parser.CriticReviews(body)
+		reviews = append(reviews, r...)
+		return pages
+	})
+
+	return reviews, err
+}
+
+// UserReviews returns all user reviews for the game detail page gameURL.
+//
+// It follows the "/user-reviews" subpage and all of its pages. Use UserReviewsPage
+// for games with a lot of reviews. It returns ErrUnsupported if the Parser is
+// no UserReviewParser.
+func (m *Metacritic) UserReviews(gameURL string) ([]*UserReview, error) {
+	return m.UserReviewsWithContext(context.Background(), gameURL)
+}
+
+// UserReviewsWithContext is like UserReviews but aborts the crawl when ctx is cancelled.
+func (m *Metacritic) UserReviewsWithContext(ctx context.Context, gameURL string) ([]*UserReview, error) {
+	parser, ok := m.Parser.(UserReviewParser)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+
+	var reviews []*UserReview
+
+	err := m.crawlPages(ctx, gameURL, "user-reviews", func(body io.Reader) int {
+		r, pages := parser.UserReviews(body)
+		reviews = append(reviews, r...)
+		return pages
+	})
+
+	return reviews, err
+}
+
+// UserReviewsPage returns the user reviews of page (starting with 0) of the
+// "/user-reviews" subpage of gameURL and the total number of pages.
+func (m *Metacritic) UserReviewsPage(gameURL string, page int) ([]*UserReview, int, error) {
+	return m.UserReviewsPageWithContext(context.Background(), gameURL, page)
+}
+
+// UserReviewsPageWithContext is like UserReviewsPage but aborts the crawl when ctx is cancelled.
+func (m *Metacritic) UserReviewsPageWithContext(ctx context.Context, gameURL string, page int) ([]*UserReview, int, error) {
+	parser, ok := m.Parser.(UserReviewParser)
+	if !ok {
+		return nil, 0, ErrUnsupported
+	}
+
+	var reviews []*UserReview
+	var pages int
+
+	err := m.crawlPage(ctx, subpageURL(gameURL, "user-reviews", page), func(body io.Reader) {
+		reviews, pages = parser.UserReviews(body)
+	})
+
+	return reviews, pages, err
+}
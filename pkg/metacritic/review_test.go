@@ -0,0 +1,156 @@
+package metacritic_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stahlstift/go-metacritic/pkg/metacritic"
+)
+
+func TestMetacritic_CriticReviews(t *testing.T) {
+	t.Parallel()
+
+	mockClient := &MockClient{}
+	mockClient.DoFn = func(req *http.Request) (response *http.Response, err error) {
+		res := httptest.NewRecorder().Result()
+
+		if req.URL.String() == "https://www.metacritic.com/game/switch/super-mario-party/critic-reviews" {
+			file, err := os.Open("./testdata/mario_party_critic_reviews.html")
+			if err != nil {
+				return nil, err
+			}
+			res.Body = file
+		}
+
+		if req.URL.String() == "https://www.metacritic.com/game/switch/super-mario-party/critic-reviews?page=1" {
+			file, err := os.Open("./testdata/mario_party_critic_reviews_page2.html")
+			if err != nil {
+				return nil, err
+			}
+			res.Body = file
+		}
+
+		return res, nil
+	}
+
+	mc := buildWithClient(mockClient)
+
+	reviews, err := mc.CriticReviews("https://www.metacritic.com/game/switch/super-mario-party")
+	if err != nil {
+		t.Fatalf("CriticReviews() returned an error '%s'", err)
+	}
+
+	if len(reviews) != 3 {
+		t.Fatalf("CriticReviews() returned %d reviews instead of 3", len(reviews))
+	}
+
+	if reviews[2].Publication != "Game Rant" {
+		t.Fatalf("CriticReviews() returned '%s' as last publication instead of 'Game Rant'", reviews[2].Publication)
+	}
+}
+
+func TestMetacritic_CriticReviewsCrawlError(t *testing.T) {
+	t.Parallel()
+
+	mockClient := &MockClient{}
+	mockClient.DoFn = func(req *http.Request) (response *http.Response, err error) {
+		return nil, fmt.Errorf("unittest")
+	}
+
+	mc := buildWithClient(mockClient)
+
+	_, err := mc.CriticReviews("https://www.metacritic.com/game/switch/super-mario-party")
+	if err == nil {
+		t.Error("CriticReviews() did not returned an error")
+	}
+}
+
+func TestMetacritic_CriticReviewsUnsupported(t *testing.T) {
+	t.Parallel()
+
+	mc := buildWithClient(mockClient)
+	mc.Parser = plainParser{}
+
+	_, err := mc.CriticReviews("https://www.metacritic.com/game/switch/super-mario-party")
+	if !errors.Is(err, metacritic.ErrUnsupported) {
+		t.Fatalf("CriticReviews() returned '%v' instead of '%s'", err, metacritic.ErrUnsupported)
+	}
+}
+
+var userReviewsClient = &MockClient{
+	DoFn: func(req *http.Request) (response *http.Response, err error) {
+		res := httptest.NewRecorder().Result()
+
+		if req.URL.String() == "https://www.metacritic.com/game/switch/super-mario-party/user-reviews" {
+			file, err := os.Open("./testdata/mario_party_user_reviews.html")
+			if err != nil {
+				return nil, err
+			}
+			res.Body = file
+		}
+
+		if req.URL.String() == "https://www.metacritic.com/game/switch/super-mario-party/user-reviews?page=1" {
+			file, err := os.Open("./testdata/mario_party_user_reviews_page2.html")
+			if err != nil {
+				return nil, err
+			}
+			res.Body = file
+		}
+
+		return res, nil
+	},
+}
+
+func TestMetacritic_UserReviews(t *testing.T) {
+	t.Parallel()
+
+	mc := buildWithClient(userReviewsClient)
+
+	reviews, err := mc.UserReviews("https://www.metacritic.com/game/switch/super-mario-party")
+	if err != nil {
+		t.Fatalf("UserReviews() returned an error '%s'", err)
+	}
+
+	if len(reviews) != 3 {
+		t.Fatalf("UserReviews() returned %d reviews instead of 3", len(reviews))
+	}
+
+	if reviews[2].Text != "Only four boards & the online mode is barely there." {
+		t.Fatalf("UserReviews() returned '%s' as last text", reviews[2].Text)
+	}
+}
+
+func TestMetacritic_UserReviewsPage(t *testing.T) {
+	t.Parallel()
+
+	mc := buildWithClient(userReviewsClient)
+
+	reviews, pages, err := mc.UserReviewsPage("https://www.metacritic.com/game/switch/super-mario-party", 1)
+	if err != nil {
+		t.Fatalf("UserReviewsPage() returned an error '%s'", err)
+	}
+
+	if pages != 2 {
+		t.Fatalf("UserReviewsPage() returned %d pages instead of 2", pages)
+	}
+
+	if len(reviews) != 1 || reviews[0].Author != "LuigiFan" {
+		t.Fatalf("UserReviewsPage() returned wrong reviews")
+	}
+}
+
+func TestMetacritic_UserReviewsUnsupported(t *testing.T) {
+	t.Parallel()
+
+	mc := buildWithClient(userReviewsClient)
+	mc.Parser = plainParser{}
+
+	_, _, err := mc.UserReviewsPage("https://www.metacritic.com/game/switch/super-mario-party", 0)
+	if !errors.Is(err, metacritic.ErrUnsupported) {
+		t.Fatalf("UserReviewsPage() returned '%v' instead of '%s'", err, metacritic.ErrUnsupported)
+	}
+}
@@ -0,0 +1,271 @@
+package metacritic
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRobotsTTL is how long a robots.txt is cached if Robots.TTL is not set.
+const DefaultRobotsTTL = 24 * time.Hour
+
+// Robots fetches, caches and evaluates the robots.txt of every host requested
+// by a DefaultCrawler, including the Crawl-delay of the matching group.
+//
+// A robots.txt answered with a 4xx status code allows everything. Robots is safe
+// for concurrent use.
+type Robots struct {
+	TTL time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*robotsHost
+}
+
+// NewRobots returns a Robots caching every robots.txt for DefaultRobotsTTL.
+func NewRobots() *Robots {
+	return &Robots{TTL: DefaultRobotsTTL}
+}
+
+type robotsHost struct {
+	mu      sync.Mutex
+	groups  []*robotsGroup
+	fetched time.Time
+	next    time.Time // earliest time for the next request because of the Crawl-delay
+}
+
+type robotsGroup struct {
+	agents     []string
+	rules      []robotsRule
+	crawlDelay time.Duration
+}
+
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+func (r *Robots) host(key string) *robotsHost {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.hosts == nil {
+		r.hosts = make(map[string]*robotsHost)
+	}
+
+	h, ok := r.hosts[key]
+	if !ok {
+		h = &robotsHost{}
+		r.hosts[key] = h
+	}
+
+	return h
+}
+
+// wait returns ErrDisallowedByRobots if userAgent may not request u and
+// otherwise blocks until the Crawl-delay for the host of u has passed.
+func (r *Robots) wait(ctx context.Context, client Client, userAgent string, u *url.URL) error {
+	if u.Path == "/robots.txt" {
+		return nil
+	}
+
+	h := r.host(u.Scheme + "://" + u.Host)
+
+	h.mu.Lock()
+	ttl := r.TTL
+	if ttl <= 0 {
+		ttl = DefaultRobotsTTL
+	}
+
+	if h.fetched.IsZero() || time.Since(h.fetched) > ttl {
+		groups, err := fetchRobots(ctx, client, userAgent, u)
+		if err != nil {
+			h.mu.Unlock()
+			return err
+		}
+
+		h.groups = groups
+		h.fetched = time.Now()
+	}
+
+	group := matchRobotsGroup(h.groups, userAgent)
+	if group != nil && !group.allowed(u) {
+		h.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrDisallowedByRobots, u)
+	}
+
+	var delay time.Duration
+	if group != nil && group.crawlDelay > 0 {
+		now := time.Now()
+		if h.next.After(now) {
+			delay = h.next.Sub(now)
+		}
+		h.next = now.Add(delay + group.crawlDelay)
+	}
+	h.mu.Unlock()
+
+	if delay > 0 {
+		return sleep(ctx, delay)
+	}
+
+	return nil
+}
+
+// fetchRobots requests and parses the robots.txt of the host of u.
+func fetchRobots(ctx context.Context, client Client, userAgent string, u *url.URL) ([]*robotsGroup, error) {
+	robotsURL := u.Scheme + "://" + u.Host + "/robots.txt"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, robotsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 400 && res.StatusCode <= 499 {
+		return nil, nil
+	}
+
+	if err := newHTTPStatusError(robotsURL, res); err != nil {
+		return nil, err
+	}
+
+	return parseRobots(res.Body), nil
+}
+
+// parseRobots parses the groups of a robots.txt.
+func parseRobots(body io.Reader) []*robotsGroup {
+	var groups []*robotsGroup
+	var group *robotsGroup
+
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+
+		i := strings.Index(line, ":")
+		if i < 0 {
+			continue
+		}
+
+		key := strings.ToLower(strings.TrimSpace(line[:i]))
+		value := strings.TrimSpace(line[i+1:])
+
+		switch key {
+		case "user-agent":
+			// consecutive user-agent lines share one group
+			if group == nil || len(group.rules) > 0 || group.crawlDelay > 0 {
+				group = &robotsGroup{}
+				groups = append(groups, group)
+			}
+			group.agents = append(group.agents, strings.ToLower(value))
+		case "allow", "disallow":
+			if group != nil && value != "" {
+				group.rules = append(group.rules, robotsRule{allow: key == "allow", pattern: value})
+			}
+		case "crawl-delay":
+			if seconds, err := strconv.ParseFloat(value, 64); group != nil && err == nil && seconds > 0 {
+				group.crawlDelay = time.Duration(seconds * float64(time.Second))
+			}
+		}
+	}
+
+	return groups
+}
+
+// matchRobotsGroup returns the group with the longest user-agent contained in
+// userAgent, the "*" group or nil.
+func matchRobotsGroup(groups []*robotsGroup, userAgent string) *robotsGroup {
+	userAgent = strings.ToLower(userAgent)
+
+	var match, wildcard *robotsGroup
+	matchLen := 0
+	for _, g := range groups {
+		for _, agent := range g.agents {
+			if agent == "*" {
+				if wildcard == nil {
+					wildcard = g
+				}
+				continue
+			}
+
+			if len(agent) > matchLen && strings.Contains(userAgent, agent) {
+				match = g
+				matchLen = len(agent)
+			}
+		}
+	}
+
+	if match != nil {
+		return match
+	}
+
+	return wildcard
+}
+
+// allowed evaluates the rules of g for u. The longest matching rule wins,
+// allow rules win over disallow rules of the same length.
+func (g *robotsGroup) allowed(u *url.URL) bool {
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+
+	allowed := true
+	longest := -1
+	for _, rule := range g.rules {
+		if !robotsMatch(rule.pattern, path) {
+			continue
+		}
+
+		if len(rule.pattern) > longest || len(rule.pattern) == longest && rule.allow {
+			allowed = rule.allow
+			longest = len(rule.pattern)
+		}
+	}
+
+	return allowed
+}
+
+// robotsMatch reports whether path matches the robots.txt pattern, which may
+// contain "*" wildcards and a trailing "$" anchor.
+func robotsMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	path = path[len(parts[0]):]
+
+	for i, part := range parts[1:] {
+		if anchored && i == len(parts)-2 {
+			return strings.HasSuffix(path, part)
+		}
+
+		j := strings.Index(path, part)
+		if j < 0 {
+			return false
+		}
+		path = path[j+len(part):]
+	}
+
+	return !anchored || path == ""
+}
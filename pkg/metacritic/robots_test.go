@@ -0,0 +1,174 @@
+package metacritic_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stahlstift/go-metacritic/pkg/metacritic"
+)
+
+const robotsTxt = `# robots.txt for unit tests
+User-agent: *
+Disallow: /search/
+Allow: /search/game/allowed
+Disallow: /*.json$
+
+User-agent: otherbot
+User-agent: unittest
+Disallow: /private
+Crawl-delay: 0.05
+`
+
+func robotsServer(robots string, status int, fetches *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			atomic.AddInt32(fetches, 1)
+			w.WriteHeader(status)
+			w.Write([]byte(robots))
+		}
+	}))
+}
+
+func TestRobots(t *testing.T) {
+	t.Parallel()
+
+	var fetches int32
+	server := robotsServer(robotsTxt, http.StatusOK, &fetches)
+	defer server.Close()
+
+	tests := []struct {
+		userAgent string
+		path      string
+		allowed   bool
+	}{
+		{"Mozilla/5.0", "/game/switch/super-mario-party", true},
+		{"Mozilla/5.0", "/search/game/Mario/results", false},
+		{"Mozilla/5.0", "/search/game/allowed", true},
+		{"Mozilla/5.0", "/data.json", false},
+		{"Mozilla/5.0", "/data.json?page=1", true},
+		{"Mozilla/5.0", "/private", true},
+		{userAgent, "/search/game/Mario/results", true},
+		{userAgent, "/private/page", false},
+	}
+
+	for _, test := range tests {
+		c := &metacritic.DefaultCrawler{
+			Client:     server.Client(),
+			Concurrent: 1,
+			UserAgent:  test.userAgent,
+			Robots:     metacritic.NewRobots(),
+		}
+
+		res := c.CrawlOne(server.URL + test.path)
+		if res.Response != nil {
+			res.Response.Body.Close()
+		}
+
+		if disallowed := errors.Is(res.Error, metacritic.ErrDisallowedByRobots); disallowed == test.allowed {
+			t.Errorf("CrawlOne('%s') as '%s' returned '%v'", test.path, test.userAgent, res.Error)
+		}
+	}
+}
+
+func TestRobotsCachedAndCrawlDelay(t *testing.T) {
+	t.Parallel()
+
+	var fetches int32
+	server := robotsServer(robotsTxt, http.StatusOK, &fetches)
+	defer server.Close()
+
+	c := &metacritic.DefaultCrawler{
+		Client:     server.Client(),
+		Concurrent: 3,
+		UserAgent:  userAgent,
+		Robots:     metacritic.NewRobots(),
+	}
+
+	start := time.Now()
+	for _, res := range c.Crawl([]string{server.URL + "/1", server.URL + "/2", server.URL + "/3"}) {
+		if res.Error != nil {
+			t.Fatalf("Crawl() returned an error '%s'", res.Error)
+		}
+		res.Response.Body.Close()
+	}
+
+	if n := atomic.LoadInt32(&fetches); n != 1 {
+		t.Fatalf("robots.txt was fetched %d times instead of once", n)
+	}
+
+	// three requests with a Crawl-delay of 50ms need at least 100ms
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Fatalf("3 requests took %s - the Crawl-delay was not applied", elapsed)
+	}
+}
+
+func TestRobotsMissing(t *testing.T) {
+	t.Parallel()
+
+	var fetches int32
+	server := robotsServer("", http.StatusNotFound, &fetches)
+	defer server.Close()
+
+	c := &metacritic.DefaultCrawler{
+		Client:     server.Client(),
+		Concurrent: 1,
+		UserAgent:  userAgent,
+		Robots:     metacritic.NewRobots(),
+	}
+
+	res := c.CrawlOne(server.URL + "/search/game/Mario/results")
+	if res.Error != nil {
+		t.Fatalf("CrawlOne() returned an error '%s' without a robots.txt", res.Error)
+	}
+	res.Response.Body.Close()
+}
+
+func TestRobotsWithHeaders(t *testing.T) {
+	t.Parallel()
+
+	var fetches int32
+	server := robotsServer(robotsTxt, http.StatusOK, &fetches)
+	defer server.Close()
+
+	profiles := []metacritic.HeaderProfile{
+		{Name: "otherbot", Header: http.Header{"User-Agent": {"otherbot"}}},
+		{Name: "browser", Header: http.Header{"User-Agent": {"Mozilla/5.0"}}},
+	}
+
+	tests := []struct {
+		userAgent string
+		path      string
+		allowed   bool
+	}{
+		{userAgent, "/search/game/Mario/results", true},
+		{userAgent, "/private", false},
+		{"", "/search/game/Mario/results", false},
+		{"", "/private", true},
+	}
+
+	for _, test := range tests {
+		c := &metacritic.DefaultCrawler{
+			Client:     server.Client(),
+			Concurrent: 1,
+			UserAgent:  test.userAgent,
+			Headers:    metacritic.NewRotatingHeaders(profiles...),
+			Robots:     &metacritic.Robots{},
+		}
+
+		// every profile is used once, the result must not depend on it
+		for i := range profiles {
+			res := c.CrawlOne(server.URL + test.path)
+			if res.Response != nil {
+				res.Response.Body.Close()
+			}
+
+			if disallowed := errors.Is(res.Error, metacritic.ErrDisallowedByRobots); disallowed == test.allowed {
+				t.Errorf("CrawlOne('%s') #%d as '%s' returned '%v'", test.path, i, test.userAgent, res.Error)
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package metacritic
+
+// ScoreBand is the color band metacritic shows a score in.
+//
+// The values match the css classes used by metacritic.
+type ScoreBand string
+
+const (
+	Positive ScoreBand = "positive" // green
+	Mixed    ScoreBand = "mixed"    // yellow
+	Negative ScoreBand = "negative" // red
+	TBD      ScoreBand = "tbd"      // no score yet
+)
+
+// ScoreDistribution is the number of positive, mixed and negative reviews of a game.
+type ScoreDistribution struct {
+	Positive int
+	Mixed    int
+	Negative int
+}
+
+// Total returns the number of reviews in the distribution.
+func (d ScoreDistribution) Total() int {
+	return d.Positive + d.Mixed + d.Negative
+}
+
+// MetaScoreBand classifies a metascore or critic review score (0 - 100).
+func MetaScoreBand(score uint8) ScoreBand {
+	switch {
+	case score >= 75:
+		return Positive
+	case score >= 50:
+		return Mixed
+	default:
+		return Negative
+	}
+}
+
+// UserScoreBand classifies a userscore or user review score (0 - 10).
+func UserScoreBand(score float32) ScoreBand {
+	switch {
+	case score >= 7.5:
+		return Positive
+	case score >= 5:
+		return Mixed
+	default:
+		return Negative
+	}
+}
+
+// MetaScoreBand returns the band of the metascore of g or TBD if there is none.
+func (g *Game) MetaScoreBand() ScoreBand {
+	if !g.HasMetaScore {
+		return TBD
+	}
+
+	return MetaScoreBand(g.MetaScore)
+}
+
+// UserScoreBand returns the band of the userscore of g or TBD if there is none.
+func (g *Game) UserScoreBand() ScoreBand {
+	if !g.HasUserScore {
+		return TBD
+	}
+
+	return UserScoreBand(g.UserScore)
+}
@@ -0,0 +1,58 @@
+package metacritic_test
+
+import (
+	"testing"
+
+	"github.com/stahlstift/go-metacritic/pkg/metacritic"
+)
+
+func TestMetaScoreBand(t *testing.T) {
+	t.Parallel()
+
+	tests := map[uint8]metacritic.ScoreBand{
+		0:   metacritic.Negative,
+		49:  metacritic.Negative,
+		50:  metacritic.Mixed,
+		74:  metacritic.Mixed,
+		75:  metacritic.Positive,
+		100: metacritic.Positive,
+	}
+
+	for score, band := range tests {
+		if b := metacritic.MetaScoreBand(score); b != band {
+			t.Errorf("MetaScoreBand(%d) returned '%s' instead of '%s'", score, b, band)
+		}
+	}
+}
+
+func TestUserScoreBand(t *testing.T) {
+	t.Parallel()
+
+	tests := map[float32]metacritic.ScoreBand{
+		0:   metacritic.Negative,
+		4.9: metacritic.Negative,
+		5:   metacritic.Mixed,
+		7.4: metacritic.Mixed,
+		7.5: metacritic.Positive,
+		10:  metacritic.Positive,
+	}
+
+	for score, band := range tests {
+		if b := metacritic.UserScoreBand(score); b != band {
+			t.Errorf("UserScoreBand(%f) returned '%s' instead of '%s'", score, b, band)
+		}
+	}
+}
+
+func TestGame_ScoreBandTBD(t *testing.T) {
+	t.Parallel()
+
+	game := &metacritic.Game{MetaScore: 80, HasMetaScore: true}
+	if game.MetaScoreBand() != metacritic.Positive {
+		t.Errorf("MetaScoreBand() returned '%s' instead of '%s'", game.MetaScoreBand(), metacritic.Positive)
+	}
+
+	if game.UserScoreBand() != metacritic.TBD {
+		t.Errorf("UserScoreBand() returned '%s' instead of '%s'", game.UserScoreBand(), metacritic.TBD)
+	}
+}
@@ -0,0 +1,83 @@
+package metacritic
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SearchHit is a game as listed on the search result page, without the data of its detail page.
+type SearchHit struct {
+	Title        string
+	URL          string
+	Platform     string
+	MetaScore    uint8
+	HasMetaScore bool   // false if metacritic shows "tbd"
+	Description  string // shortened by metacritic
+	ReleaseYear  int    // 0 if unknown, the search result page shows no full date
+}
+
+// SearchHitParser is a Parser which extracts the listed games from the search result page.
+//
+// It returns the games and the number of pages the search results are spread over.
+// SearchSummaries requires the Parser of Metacritic to implement it.
+type SearchHitParser interface {
+	SearchHits(body io.Reader) ([]*SearchHit, int)
+}
+
+// SearchSummaries returns the games found for title and platform as listed on
+// the search result page.
+//
+// Unlike Search it does not crawl the detail pages, so it needs a single request
+// unless MaxPages is set. It returns ErrUnsupported if the Parser is no SearchHitParser.
+func (m *Metacritic) SearchSummaries(title string, platform Platform) ([]*SearchHit, error) {
+	return m.SearchSummariesWithContext(context.Background(), title, platform)
+}
+
+// SearchSummariesWithContext is like SearchSummaries but aborts the crawl when
+// ctx is cancelled or its deadline is exceeded, returning ctx.Err().
+func (m *Metacritic) SearchSummariesWithContext(ctx context.Context, title string, platform Platform) (hits []*SearchHit, err error) {
+	ctx, span := m.tracer().Start(ctx, "metacritic.SearchSummaries", trace.WithAttributes(
+		attribute.String("metacritic.title", title),
+		attribute.String("metacritic.platform", string(platform)),
+	))
+	defer func() {
+		span.SetAttributes(attribute.Int("metacritic.results", len(hits)))
+		endSpan(span, err)
+	}()
+
+	parser, ok := m.Parser.(SearchHitParser)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+
+	seen := make(map[string]bool)
+	err = m.searchPages(ctx, title, platform, &SearchReport{}, func(body io.Reader) (int, int) {
+		found, pages := parser.SearchHits(body)
+		for _, hit := range found {
+			if !seen[hit.URL] {
+				seen[hit.URL] = true
+				hits = append(hits, hit)
+			}
+		}
+		return len(hits), pages
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if m.MaxResults > 0 && len(hits) > m.MaxResults {
+		hits = hits[:m.MaxResults]
+	}
+
+	loggerOrDiscard(m.Logger).DebugContext(ctx, "metacritic: search summaries",
+		slog.String("title", title),
+		slog.String("platform", string(platform)),
+		slog.Int("results", len(hits)),
+	)
+
+	return hits, nil
+}
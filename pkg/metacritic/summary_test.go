@@ -0,0 +1,81 @@
+package metacritic_test
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stahlstift/go-metacritic/pkg/metacritic"
+)
+
+func TestMetacritic_SearchSummaries(t *testing.T) {
+	t.Parallel()
+
+	var requests int32
+	client := &MockClient{}
+	client.DoFn = func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&requests, 1)
+		return mockClient.Do(req)
+	}
+	mc := buildWithClient(client)
+
+	hits, err := mc.SearchSummaries("Mario", metacritic.Switch)
+	if err != nil {
+		t.Fatalf("SearchSummaries() returned an error '%s'", err)
+	}
+
+	if len(hits) != 2 || hits[0].Title != "Super Mario Party" || hits[1].Title != "Super Mario Odyssey" {
+		t.Fatalf("SearchSummaries() returned %d hits", len(hits))
+	}
+	if requests != 1 {
+		t.Fatalf("SearchSummaries() sent %d requests instead of 1", requests)
+	}
+}
+
+func TestMetacritic_SearchSummariesPagination(t *testing.T) {
+	t.Parallel()
+
+	mc := buildWithClient(mockClient)
+	mc.MaxPages = 2
+
+	hits, err := mc.SearchSummaries("Mario", metacritic.Switch)
+	if err != nil {
+		t.Fatalf("SearchSummaries() returned an error '%s'", err)
+	}
+
+	var titles []string
+	for _, hit := range hits {
+		titles = append(titles, hit.Title)
+	}
+	if len(hits) != 3 || hits[2].Title != "Mario Tennis Aces" {
+		t.Fatalf("SearchSummaries() returned %v instead of 3 games without duplicates", titles)
+	}
+}
+
+func TestMetacritic_SearchSummariesStatusError(t *testing.T) {
+	t.Parallel()
+
+	client := &MockClient{}
+	client.DoFn = func(req *http.Request) (*http.Response, error) {
+		res, _ := mockClient.Do(req)
+		res.StatusCode = http.StatusNotFound
+		return res, nil
+	}
+	mc := buildWithClient(client)
+
+	if _, err := mc.SearchSummaries("Mario", metacritic.Switch); !errors.Is(err, metacritic.ErrNotFound) {
+		t.Fatalf("SearchSummaries() returned '%v' instead of '%s'", err, metacritic.ErrNotFound)
+	}
+}
+
+func TestMetacritic_SearchSummariesUnsupported(t *testing.T) {
+	t.Parallel()
+
+	mc := buildWithClient(mockClient)
+	mc.Parser = plainParser{}
+
+	if _, err := mc.SearchSummaries("Mario", metacritic.Switch); !errors.Is(err, metacritic.ErrUnsupported) {
+		t.Fatalf("SearchSummaries() returned '%v' instead of '%s'", err, metacritic.ErrUnsupported)
+	}
+}
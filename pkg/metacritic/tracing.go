@@ -0,0 +1,60 @@
+package metacritic
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of all spans.
+const tracerName = "github.com/stahlstift/go-metacritic/pkg/metacritic"
+
+// tracer returns the tracer of the TracerProvider or the global one if none is set.
+func (m *Metacritic) tracer() trace.Tracer {
+	if m.TracerProvider != nil {
+		return m.TracerProvider.Tracer(tracerName)
+	}
+
+	return otel.GetTracerProvider().Tracer(tracerName)
+}
+
+// spanTracer returns the tracer of the span in ctx, so spans of a Crawler are
+// children of the search span. Without a span in ctx no spans are recorded.
+func spanTracer(ctx context.Context) trace.Tracer {
+	return trace.SpanFromContext(ctx).TracerProvider().Tracer(tracerName)
+}
+
+// endSpan records err on span and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// resultAttributes returns the span attributes of result.
+func resultAttributes(result *Result) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.Int("metacritic.attempts", result.Attempts),
+		attribute.Bool("metacritic.cached", result.Cached),
+	}
+
+	statusCode := 0
+	var statusErr *HTTPStatusError
+	switch {
+	case result.Response != nil:
+		statusCode = result.Response.StatusCode
+	case errors.As(result.Error, &statusErr):
+		statusCode = statusErr.StatusCode
+	}
+	if statusCode > 0 {
+		attrs = append(attrs, attribute.Int("http.response.status_code", statusCode))
+	}
+
+	return attrs
+}
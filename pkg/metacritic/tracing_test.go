@@ -0,0 +1,60 @@
+package metacritic_test
+
+import (
+	"testing"
+
+	"github.com/stahlstift/go-metacritic/pkg/metacritic"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestMetacritic_SearchBestMatchSpans(t *testing.T) {
+	t.Parallel()
+
+	recorder := tracetest.NewSpanRecorder()
+	mc := buildWithClient(mockClient)
+	mc.TracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	if _, err := mc.FindBestMatch("Mario", metacritic.Switch); err != nil {
+		t.Fatalf("FindBestMatch() returned an error '%s'", err)
+	}
+
+	byName := map[string][]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		byName[span.Name()] = append(byName[span.Name()], span)
+	}
+
+	for _, name := range []string{"metacritic.SearchBestMatch", "metacritic.Search", "metacritic.ParseSearch"} {
+		if len(byName[name]) != 1 {
+			t.Fatalf("FindBestMatch() recorded %d '%s' spans instead of 1", len(byName[name]), name)
+		}
+	}
+
+	root := byName["metacritic.SearchBestMatch"][0]
+	search := byName["metacritic.Search"][0]
+	if search.Parent().SpanID() != root.SpanContext().SpanID() {
+		t.Fatalf("Search span is not a child of the SearchBestMatch span")
+	}
+
+	wantAttrs := map[attribute.Key]string{
+		"metacritic.title":    "Mario",
+		"metacritic.platform": string(metacritic.Switch),
+	}
+	for _, kv := range search.Attributes() {
+		if want, ok := wantAttrs[kv.Key]; ok && kv.Value.AsString() != want {
+			t.Fatalf("Search span has %s='%s' instead of '%s'", kv.Key, kv.Value.AsString(), want)
+		}
+	}
+
+	crawls := byName["metacritic.Crawl"]
+	parses := byName["metacritic.ParseGame"]
+	if len(crawls) < 2 || len(parses) != len(crawls)-1 {
+		t.Fatalf("FindBestMatch() recorded %d crawl and %d parse spans", len(crawls), len(parses))
+	}
+	for _, span := range append(crawls, parses...) {
+		if span.Parent().SpanID() != search.SpanContext().SpanID() {
+			t.Fatalf("'%s' span is not a child of the Search span", span.Name())
+		}
+	}
+}